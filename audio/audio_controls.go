@@ -0,0 +1,160 @@
+package audio
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// volumeSliderWidth is the width of the volume slider in the control bar.
+const volumeSliderWidth = 100
+
+var _ fyne.WidgetRenderer = (*playerRenderer)(nil)
+
+// playerRenderer is the renderer of the audio Player. It is a compact control bar
+// with the play button, the position cursor, the time and the volume.
+type playerRenderer struct {
+	parent       *Player
+	playButton   *widget.Button
+	muteButton   *widget.Button
+	cursor       *widget.Slider
+	volumeSlider *widget.Slider
+	timeText     *widget.Label
+	currentTime  time.Duration
+	totalTime    time.Duration
+	controls     *fyne.Container
+	manualSeeked bool
+}
+
+// newPlayerRenderer creates the control bar of the player.
+func newPlayerRenderer(parent *Player) *playerRenderer {
+	r := &playerRenderer{
+		parent:       parent,
+		manualSeeked: true,
+	}
+
+	r.timeText = widget.NewLabel("00:00:00 / 00:00:00")
+	r.playButton = r.createPlayButton()
+	r.muteButton = r.createMuteButton()
+	r.cursor = r.createPositionCursor()
+	r.volumeSlider = r.createVolumeSlider()
+
+	r.controls = container.NewBorder(
+		nil, nil,
+		r.playButton, // left
+		container.NewHBox( // right
+			r.timeText,
+			r.muteButton,
+			container.NewGridWrap(
+				fyne.NewSize(volumeSliderWidth, r.volumeSlider.MinSize().Height),
+				r.volumeSlider,
+			),
+		),
+		r.cursor,
+	)
+	return r
+}
+
+// Destroy is an internal function from the fyne.WidgetRenderer interface.
+func (r *playerRenderer) Destroy() { /* no op */ }
+
+// Layout implements the fyne.WidgetRenderer interface.
+func (r *playerRenderer) Layout(size fyne.Size) {
+	r.controls.Resize(size)
+}
+
+// MinSize implements the fyne.WidgetRenderer interface.
+func (r *playerRenderer) MinSize() fyne.Size {
+	return r.controls.MinSize()
+}
+
+// Objects implements the fyne.WidgetRenderer interface.
+func (r *playerRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.controls}
+}
+
+// Refresh implements the fyne.WidgetRenderer interface. It refreshes the time, the cursor and the buttons.
+func (r *playerRenderer) Refresh() {
+	currentTime := time.Time{}.Add(r.currentTime).Format(streamer.TimeFormat)
+	totalTime := time.Time{}.Add(r.totalTime).Format(streamer.TimeFormat)
+	r.timeText.SetText(fmt.Sprintf("%s / %s", currentTime, totalTime))
+
+	r.manualSeeked = false
+	r.cursor.Max = float64(r.totalTime.Milliseconds())
+	r.cursor.SetValue(float64(r.currentTime.Milliseconds()))
+	r.manualSeeked = true
+
+	if r.parent.IsPlaying() {
+		r.playButton.SetIcon(theme.MediaPauseIcon())
+	} else {
+		r.playButton.SetIcon(theme.MediaPlayIcon())
+	}
+	if r.parent.IsMuted() {
+		r.muteButton.SetIcon(theme.VolumeMuteIcon())
+	} else {
+		r.muteButton.SetIcon(theme.VolumeUpIcon())
+	}
+	r.controls.Refresh()
+}
+
+// setPosition updates the time and the cursor.
+func (r *playerRenderer) setPosition(pos, duration time.Duration) {
+	r.currentTime = pos
+	r.totalTime = duration
+	r.Refresh()
+}
+
+func (r *playerRenderer) createMuteButton() *widget.Button {
+	muteButton := widget.NewButtonWithIcon("", theme.VolumeUpIcon(), func() {
+		r.parent.ToggleMute()
+		r.Refresh()
+	})
+	muteButton.Importance = widget.LowImportance
+	return muteButton
+}
+
+func (r *playerRenderer) createPlayButton() *widget.Button {
+	playButton := widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {
+		if r.parent.pipeline == nil {
+			return
+		}
+		if r.parent.IsPlaying() {
+			r.parent.Pause()
+		} else {
+			r.parent.Play()
+		}
+	})
+	playButton.Importance = widget.LowImportance
+	return playButton
+}
+
+func (r *playerRenderer) createPositionCursor() *widget.Slider {
+	cursor := widget.NewSlider(0, 100)
+	cursor.OnChanged = func(value float64) {
+		if !r.manualSeeked {
+			return
+		}
+		pos := time.Duration(value) * time.Millisecond
+		if err := r.parent.Seek(pos); err != nil {
+			return
+		}
+		r.currentTime = pos
+		r.Refresh()
+	}
+	return cursor
+}
+
+func (r *playerRenderer) createVolumeSlider() *widget.Slider {
+	volumeSlider := widget.NewSlider(0, 1)
+	volumeSlider.Step = 0.01
+	volumeSlider.Value = 1
+	volumeSlider.OnChanged = func(value float64) {
+		r.parent.SetVolume(value)
+	}
+	return volumeSlider
+}
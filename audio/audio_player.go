@@ -0,0 +1,309 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+	"github.com/metal3d/fyne-streamer/playlist"
+)
+
+var _ fyne.Widget = (*Player)(nil)
+var _ utils.MediaAudioLevel = (*Player)(nil)
+var _ utils.MediaControl = (*Player)(nil)
+var _ utils.MediaDuration = (*Player)(nil)
+var _ utils.MediaOpener = (*Player)(nil)
+var _ utils.MediaSeeker = (*Player)(nil)
+var _ utils.MediaSpectrum = (*Player)(nil)
+
+// positionInterval is the interval between two position updates while playing.
+const positionInterval = 200 * time.Millisecond
+
+// Player widget plays audio files or streams, with a compact control bar.
+type Player struct {
+	widget.BaseWidget
+	pipeline          *gst.Pipeline
+	bus               *gst.Bus
+	duration          time.Duration
+	onEOS             func()
+	onTitle           func(string)
+	onPositionChanged func(time.Duration)
+	onSpectrum        func([]float64)
+	onAudioLevel      func(rms, peak []float64)
+	onMetadata        func(streamer.Metadata)
+	metadata          streamer.Metadata
+	metadataLock      sync.Mutex         // protects the metadata that are set by the bus watch
+	playlist          *playlist.Playlist // advanced at the end of the stream, nil if there is none
+	spectrumBands     int                // number of bands of the spectrum, 0 for the element default
+	spectrumInterval  time.Duration      // interval between two spectrum messages, 0 for the element default
+	cancelTicker      context.CancelFunc // stops the position updates
+	renderer          *playerRenderer
+}
+
+// NewPlayer creates a new audio player widget.
+func NewPlayer() *Player {
+	utils.GstreamerInit()
+	p := &Player{}
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+// CreateRenderer creates a renderer for the audio player.
+//
+// Implements: fyne.Widget
+func (p *Player) CreateRenderer() fyne.WidgetRenderer {
+	r := newPlayerRenderer(p)
+	p.renderer = r
+	return r
+}
+
+// CurrentPosition returns the current position of the stream in time.
+func (p *Player) CurrentPosition() (time.Duration, error) {
+	if p.pipeline == nil {
+		return 0, streamer.ErrNoPipeline
+	}
+	ok, pos := p.pipeline.QueryPosition(gst.FormatTime)
+	if !ok {
+		return 0, streamer.ErrPositionUnseekable
+	}
+	return time.Duration(pos), nil
+}
+
+// Duration returns the duration of the stream if possible.
+func (p *Player) Duration() (time.Duration, error) {
+	if p.pipeline == nil {
+		return 0, streamer.ErrNoPipeline
+	}
+	if p.duration != 0 {
+		return p.duration, nil
+	}
+	ok, duration := p.pipeline.QueryDuration(gst.FormatTime)
+	if !ok {
+		return 0, streamer.ErrNoDuration
+	}
+	p.duration = time.Duration(duration)
+	return p.duration, nil
+}
+
+// IsMuted returns true if the audio is muted.
+func (p *Player) IsMuted() bool {
+	volumeElement := p.volumeElement()
+	if volumeElement == nil {
+		return false
+	}
+	isMuted, err := volumeElement.GetProperty("mute")
+	if err != nil {
+		fyne.LogError("Failed to get the mute property", err)
+		return false
+	}
+	return isMuted.(bool)
+}
+
+// IsPlaying returns true if the pipeline is in the playing state.
+func (p *Player) IsPlaying() bool {
+	if p.pipeline == nil {
+		return false
+	}
+	return p.pipeline.GetCurrentState() == gst.StatePlaying
+}
+
+// Metadata returns the metadata found in the tags of the media so far. The tags are found while
+// prerolling and playing, use SetOnMetadata to be notified.
+func (p *Player) Metadata() streamer.Metadata {
+	p.metadataLock.Lock()
+	defer p.metadataLock.Unlock()
+	return p.metadata
+}
+
+// Mute the audio.
+func (p *Player) Mute() {
+	volumeElement := p.volumeElement()
+	if volumeElement == nil {
+		return
+	}
+	volumeElement.SetProperty("mute", true)
+}
+
+// Pause the stream if the pipeline is not nil. The controls are refreshed when the pipeline is paused.
+func (p *Player) Pause() error {
+	if p.pipeline == nil {
+		return streamer.ErrNoPipeline
+	}
+	p.stopPositionUpdates()
+	return p.pipeline.SetState(gst.StatePaused)
+}
+
+// Pipeline returns the gstreamer pipeline.
+func (p *Player) Pipeline() *gst.Pipeline {
+	return p.pipeline
+}
+
+// Play the stream if the pipeline is not nil.
+func (p *Player) Play() error {
+	if p.pipeline == nil {
+		return streamer.ErrNoPipeline
+	}
+	if err := p.pipeline.SetState(gst.StatePlaying); err != nil {
+		return err
+	}
+	p.startPositionUpdates()
+	p.Refresh()
+	return nil
+}
+
+// Playlist returns the attached playlist, nil if there is none.
+func (p *Player) Playlist() *playlist.Playlist {
+	return p.playlist
+}
+
+// Seek the position to "pos" Nanoseconds. Set the playing stream to this time position.
+// If the element or the pipeline cannot be seekable, the operation is cancelled.
+func (p *Player) Seek(pos time.Duration) error {
+	if p.pipeline == nil {
+		return streamer.ErrNoPipeline
+	}
+	query := gst.NewSeekingQuery(gst.FormatTime)
+	if !p.pipeline.Query(query) {
+		return streamer.ErrSeekUnsupported
+	}
+	if !p.pipeline.SeekTime(pos, gst.SeekFlagFlush) {
+		return streamer.ErrSeekFailed
+	}
+	return nil
+}
+
+// SetOnAudioLevel set the function that is called with the RMS and peak levels of each audio channel, in dB.
+// The pipeline must have a "level" element named with LevelElementName, which is the case of the
+// default pipeline.
+func (p *Player) SetOnAudioLevel(f func(rms, peak []float64)) {
+	p.onAudioLevel = f
+	utils.PostAudioMessages(p.pipeline, p.onSpectrum != nil, p.onAudioLevel != nil)
+}
+
+// SetOnEOS set the function to call when EOS is reached in the pipeline. E.g. when the track ends.
+func (p *Player) SetOnEOS(f func()) {
+	p.onEOS = f
+}
+
+// SetOnMetadata set the function that is called when tags of the media are found, with all the
+// metadata found so far. See Metadata.
+func (p *Player) SetOnMetadata(f func(streamer.Metadata)) {
+	p.onMetadata = f
+}
+
+// SetOnPositionChanged set the function that is called regularly with the current position while playing.
+func (p *Player) SetOnPositionChanged(f func(time.Duration)) {
+	p.onPositionChanged = f
+}
+
+// SetOnSpectrum set the function that is called with the magnitudes of the audio frequency bands, in dB,
+// from the lowest to the highest frequency. The pipeline must have a "spectrum" element named with
+// SpectrumElementName, which is the case of the default pipeline.
+func (p *Player) SetOnSpectrum(f func([]float64)) {
+	p.onSpectrum = f
+	utils.PostAudioMessages(p.pipeline, p.onSpectrum != nil, p.onAudioLevel != nil)
+}
+
+// SetOnTitle set the function that is called when the title tag of the stream is found.
+func (p *Player) SetOnTitle(f func(string)) {
+	p.onTitle = f
+}
+
+// SetPlaylist attaches the player to the playlist: the entries selected in the playlist are opened
+// in the player, and the next entry is played at the end of the stream instead of going back to the
+// beginning. Use nil to detach the playlist.
+func (p *Player) SetPlaylist(list *playlist.Playlist) {
+	if p.playlist != nil && p.playlist != list {
+		p.playlist.Detach(p)
+	}
+	p.playlist = list
+	if list != nil {
+		list.Attach(p)
+	}
+}
+
+// SetSpectrumBands sets the number of frequency bands of the spectrum. The setting is kept
+// for the next opened pipelines.
+func (p *Player) SetSpectrumBands(bands int) error {
+	if bands <= 0 {
+		return fmt.Errorf("%w: %d bands", streamer.ErrInvalidSpectrum, bands)
+	}
+	p.spectrumBands = bands
+	if p.pipeline == nil {
+		return nil
+	}
+	return utils.ConfigureSpectrum(p.pipeline, bands, 0)
+}
+
+// SetSpectrumInterval sets the interval between two spectrum updates. The setting is kept
+// for the next opened pipelines.
+func (p *Player) SetSpectrumInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: interval %v", streamer.ErrInvalidSpectrum, interval)
+	}
+	p.spectrumInterval = interval
+	if p.pipeline == nil {
+		return nil
+	}
+	return utils.ConfigureSpectrum(p.pipeline, 0, interval)
+}
+
+// SetVolume sets the volume of the audio, between 0 and 1.
+func (p *Player) SetVolume(volume float64) {
+	if volume < 0 || volume > 1 {
+		return
+	}
+	volumeElement := p.volumeElement()
+	if volumeElement == nil {
+		return
+	}
+	volumeElement.SetProperty("volume", volume)
+}
+
+// Stop the stream if the pipeline is not nil.
+func (p *Player) Stop() error {
+	if p.pipeline == nil {
+		return streamer.ErrNoPipeline
+	}
+	p.stopPositionUpdates()
+	defer p.Refresh()
+	return p.pipeline.SetState(gst.StateNull)
+}
+
+// ToggleMute mutes or unmutes the audio.
+func (p *Player) ToggleMute() {
+	if p.IsMuted() {
+		p.Unmute()
+	} else {
+		p.Mute()
+	}
+}
+
+// Unmute the audio.
+func (p *Player) Unmute() {
+	volumeElement := p.volumeElement()
+	if volumeElement == nil {
+		return
+	}
+	volumeElement.SetProperty("mute", false)
+}
+
+// Volume returns the volume of the audio, between 0 and 1.
+func (p *Player) Volume() float64 {
+	volumeElement := p.volumeElement()
+	if volumeElement == nil {
+		return 0
+	}
+	volume, err := volumeElement.GetProperty("volume")
+	if err != nil {
+		fyne.LogError("Failed to get the volume property", err)
+		return 0
+	}
+	return volume.(float64)
+}
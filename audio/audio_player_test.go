@@ -0,0 +1,97 @@
+package audio
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+	"github.com/go-gst/go-gst/gst"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPipeline = `
+    audiotestsrc name={{ .InputElementName }} num-buffers=100 !
+    audioconvert !
+    volume name={{ .VolumeElementName }} !
+    fakesink sync=true
+    `
+
+func TestCreatePlayer(t *testing.T) {
+	player := NewPlayer()
+	_ = test.WidgetRenderer(player)
+	assert.Nil(t, player.Pipeline())
+	assert.NotNil(t, player.Play())
+}
+
+func TestOpenUnsupportedScheme(t *testing.T) {
+	player := NewPlayer()
+	uri, _ := storage.ParseURI("ftp://localhost/podcast.mp3")
+	err := player.Open(uri)
+	assert.NotNil(t, err)
+}
+
+func TestPlayPause(t *testing.T) {
+	player := NewPlayer()
+	_ = test.WidgetRenderer(player)
+
+	err := player.SetPipelineFromString(testPipeline)
+	assert.Nil(t, err)
+	assert.NotNil(t, player.Pipeline())
+
+	err = player.Play()
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, gst.StatePlaying, player.Pipeline().GetCurrentState())
+	assert.True(t, player.IsPlaying())
+
+	pos, err := player.CurrentPosition()
+	assert.Nil(t, err)
+	assert.True(t, pos > 0)
+
+	err = player.Pause()
+	assert.Nil(t, err)
+	time.Sleep(200 * time.Millisecond)
+	assert.False(t, player.IsPlaying())
+
+	// the button is refreshed once the pipeline is paused
+	assert.Eventually(t, func() bool {
+		return player.renderer.playButton.Icon == theme.MediaPlayIcon()
+	}, time.Second, 50*time.Millisecond)
+}
+
+func TestVolume(t *testing.T) {
+	player := NewPlayer()
+	_ = test.WidgetRenderer(player)
+	err := player.SetPipelineFromString(testPipeline)
+	assert.Nil(t, err)
+
+	player.SetVolume(0.5)
+	assert.Equal(t, 0.5, player.Volume())
+
+	assert.False(t, player.IsMuted())
+	player.ToggleMute()
+	assert.True(t, player.IsMuted())
+	player.ToggleMute()
+	assert.False(t, player.IsMuted())
+}
+
+func TestEOS(t *testing.T) {
+	player := NewPlayer()
+	_ = test.WidgetRenderer(player)
+	err := player.SetPipelineFromString(testPipeline)
+	assert.Nil(t, err)
+
+	eos := make(chan struct{}, 1)
+	player.SetOnEOS(func() {
+		eos <- struct{}{}
+	})
+	player.Play()
+
+	select {
+	case <-eos:
+	case <-time.After(10 * time.Second):
+		t.Fatal("EOS not reached")
+	}
+}
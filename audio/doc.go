@@ -0,0 +1,28 @@
+/*
+Package audio proposes a widget to play audio files or streams using GStreamer.
+
+The Player widget is a compact control bar (play/pause, position, time and volume) that
+plays podcasts, music files or any media that GStreamer can decode. The video streams
+of the media are ignored.
+
+	player := audio.NewPlayer()
+	player.Open(uri)
+	player.Play()
+
+As for the video widgets, you can create your own GStreamer pipeline. There is no mandatory
+element, but you should name the volume element with VolumeElementName to let the player
+control the volume.
+
+For example:
+
+	pipeline := `
+	audiotestsrc name={{ .InputElementName }} !
+	audioconvert !
+	volume name={{ .VolumeElementName }} !
+	autoaudiosink
+	`
+	player := audio.NewPlayer()
+	player.SetPipelineFromString(pipeline)
+	player.Play()
+*/
+package audio
@@ -0,0 +1,190 @@
+package audio
+
+import (
+	"context"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+)
+
+func (p *Player) reset() {
+	p.stopPositionUpdates()
+	if p.pipeline != nil {
+		if err := p.pipeline.SetState(gst.StateNull); err != nil {
+			fyne.LogError("Failed to set pipeline to null", err)
+		} else {
+			p.pipeline.Clear()
+		}
+	}
+	p.pipeline = nil
+	p.duration = 0
+	p.metadataLock.Lock()
+	p.metadata = streamer.Metadata{}
+	p.metadataLock.Unlock()
+}
+
+func (p *Player) createBus() {
+	if p.pipeline == nil {
+		return
+	}
+	pipeline := p.pipeline
+	bus := pipeline.GetPipelineBus()
+	bus.AddWatch(func(msg *gst.Message) bool {
+		switch msg.Type() {
+		case gst.MessageEOS:
+			p.endOfStream()
+		case gst.MessageStateChanged:
+			// the state changes can be asynchronous, the buttons are refreshed when they are done
+			if msg.Source() == pipeline.GetName() {
+				p.Refresh()
+			}
+		case gst.MessageElement:
+			p.handleElementMessage(msg)
+		case gst.MessageError:
+			fyne.LogError("Pipeline error", msg.ParseError())
+		case gst.MessageTag:
+			p.handleTagMessage(msg)
+		}
+		return true
+	})
+	p.bus = bus
+}
+
+// configureSpectrum applies the spectrum settings to a new pipeline. The spectrum element is optional.
+func (p *Player) configureSpectrum() {
+	if p.spectrumBands == 0 && p.spectrumInterval == 0 {
+		return
+	}
+	err := utils.ConfigureSpectrum(p.pipeline, p.spectrumBands, p.spectrumInterval)
+	if err != nil && err != streamer.ErrNoSpectrum {
+		fyne.LogError("Failed to configure the spectrum", err)
+	}
+}
+
+// handleTagMessage adds the tags of the message to the metadata, and calls the title and
+// metadata callbacks.
+func (p *Player) handleTagMessage(msg *gst.Message) {
+	tags := msg.ParseTags()
+	if tags == nil {
+		return
+	}
+	title, ok := tags.GetString(gst.TagTitle)
+	if p.onTitle != nil && ok {
+		p.onTitle(title)
+	}
+
+	p.metadataLock.Lock()
+	p.metadata.AddTags(tags)
+	metadata := p.metadata
+	p.metadataLock.Unlock()
+
+	if p.onMetadata != nil {
+		p.onMetadata(metadata)
+	}
+}
+
+// handleElementMessage dispatches the messages posted on the bus by the elements of the pipeline.
+func (p *Player) handleElementMessage(msg *gst.Message) {
+	structure := msg.GetStructure()
+	if structure == nil {
+		return
+	}
+	switch {
+	case p.onSpectrum != nil && structure.Name() == "spectrum":
+		if magnitudes := utils.SpectrumMagnitudes(structure); magnitudes != nil {
+			p.onSpectrum(magnitudes)
+		}
+	case p.onAudioLevel != nil && structure.Name() == "level":
+		if rms, peak := utils.AudioLevels(structure); rms != nil {
+			p.onAudioLevel(rms, peak)
+		}
+	}
+}
+
+// endOfStream plays the next entry of the playlist, or pauses the stream and goes back to the
+// beginning, so that the player can be restarted.
+func (p *Player) endOfStream() {
+	p.stopPositionUpdates()
+	if p.onEOS != nil {
+		p.onEOS()
+	}
+	if p.playlist != nil {
+		// the pipeline can't be replaced by Open from its own bus watch
+		go func() {
+			if !p.playlist.Advance() {
+				p.rewind()
+			}
+		}()
+		return
+	}
+	p.rewind()
+}
+
+// rewind pauses the stream and goes back to the beginning.
+func (p *Player) rewind() {
+	if err := p.pipeline.SetState(gst.StatePaused); err != nil {
+		fyne.LogError("Failed to set pipeline to paused", err)
+	}
+	p.Seek(0)
+	p.updatePosition()
+	p.Refresh()
+}
+
+// startPositionUpdates starts a goroutine that updates the position while playing.
+func (p *Player) startPositionUpdates() {
+	p.stopPositionUpdates()
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancelTicker = cancel
+	go func() {
+		ticker := time.NewTicker(positionInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.updatePosition()
+			}
+		}
+	}()
+}
+
+// stopPositionUpdates stops the goroutine started by startPositionUpdates.
+func (p *Player) stopPositionUpdates() {
+	if p.cancelTicker == nil {
+		return
+	}
+	p.cancelTicker()
+	p.cancelTicker = nil
+}
+
+// updatePosition sends the current position to the callback and to the controls.
+func (p *Player) updatePosition() {
+	pos, err := p.CurrentPosition()
+	if err != nil {
+		return
+	}
+	duration, _ := p.Duration()
+	if p.renderer != nil {
+		p.renderer.setPosition(pos, duration)
+	}
+	if p.onPositionChanged != nil {
+		p.onPositionChanged(pos)
+	}
+}
+
+// volumeElement returns the volume element of the pipeline, or nil if it doesn't exist.
+func (p *Player) volumeElement() *gst.Element {
+	if p.pipeline == nil {
+		return nil
+	}
+	// custom pipelines may have no volume element, it's not an error
+	volumeElement, err := p.pipeline.GetElementByName(streamer.VolumeElementName)
+	if err != nil {
+		return nil
+	}
+	return volumeElement
+}
@@ -0,0 +1,118 @@
+package audio
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+	"github.com/metal3d/fyne-streamer/playlist"
+)
+
+// Open opens the given location. It can be a file URI, an http or https URL.
+//
+// If the location is a playlist file (M3U, PLS or XSPF), its entries replace the ones of the
+// attached playlist, or of a new playlist that is attached to the player, and the first entry
+// is opened. See SetPlaylist.
+func (p *Player) Open(u fyne.URI) error {
+	if playlist.DetectFormat(u) != playlist.FormatUnknown {
+		return p.openPlaylist(u)
+	}
+	return p.openMedia(u)
+}
+
+// openPlaylist loads the entries of the playlist file in the attached playlist, or in a new one,
+// and opens the first entry.
+func (p *Player) openPlaylist(u fyne.URI) error {
+	loaded, err := playlist.Load(u)
+	if err != nil {
+		return err
+	}
+	if p.playlist == nil {
+		p.SetPlaylist(playlist.New())
+	}
+	p.playlist.Clear()
+	p.playlist.AddEntries(loaded.Entries()...)
+	entry, err := p.playlist.Select(0)
+	if err != nil {
+		return err
+	}
+	return p.openMedia(entry.URI)
+}
+
+// openMedia opens the media at the given location.
+// The pipeline has this structure:
+//
+//	+----------------------------+
+//	|  filesrc or souphttpsrc    |
+//	+-------------+--------------+
+//	              ↓
+//	+-------------+--------------+
+//	|         decodebin          |
+//	+-------------+--------------+
+//	              ↓
+//	+-------------+--------------+
+//	| audioconvert ! scaletempo  |
+//	+-------------+--------------+
+//	              ↓
+//	+-------------+--------------+
+//	| audioresample ! spectrum   |
+//	+-------------+--------------+
+//	              ↓
+//	+-------------+--------------+
+//	|      volume ! level        |
+//	+-------------+--------------+
+//	              ↓
+//	+-------------+--------------+
+//	|       autoaudiosink        |
+//	+----------------------------+
+//
+// The video streams, if any, are not linked.
+func (p *Player) openMedia(u fyne.URI) error {
+	source, err := utils.SourceElement(u)
+	if err != nil {
+		return err
+	}
+
+	pipeline := `
+    # the source is sent to decoder
+    %[1]s name={{ .InputElementName }} !
+    decodebin name={{ .DecodeElementName }} use-buffering=true
+
+    # manage the sound
+    {{ .DecodeElementName }}. !
+    queue !
+    audioconvert !
+    scaletempo !
+    audioconvert !
+    audioresample !
+    spectrum name={{ .SpectrumElementName }} post-messages=false !
+    volume name={{ .VolumeElementName }} !
+    level name={{ .LevelElementName }} post-messages=false !
+    autoaudiosink sync=true
+    `
+	return p.SetPipelineFromString(fmt.Sprintf(pipeline, source))
+}
+
+// SetPipelineFromString creates a pipeline from a string. The string is a GStreamer pipeline description
+// that is a template (text/template) with the ElementMap as data. So you can use the
+// ElementName constants in the template.
+//
+// It also upports comments starting with #.
+//
+// There is no mandatory element, but the volume can only be controlled if the pipeline has
+// a "volume" element named with VolumeElementName, and the spectrum is only sent with a
+// "spectrum" element named with SpectrumElementName.
+func (p *Player) SetPipelineFromString(pipeline string) error {
+	p.reset()
+
+	pipelineObj, err := utils.NewPipelineFromTemplate(pipeline)
+	if err != nil {
+		return err
+	}
+
+	p.pipeline = pipelineObj
+	p.createBus()
+	p.configureSpectrum()
+	utils.PostAudioMessages(p.pipeline, p.onSpectrum != nil, p.onAudioLevel != nil)
+	return nil
+}
@@ -0,0 +1,276 @@
+package streamer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-gst/go-gst/gst"
+)
+
+// Classes of the capture devices, see Device.
+const (
+	VideoSourceClass = "Video/Source"
+	AudioSourceClass = "Audio/Source"
+)
+
+// devicePathProperties are the properties of the devices that give their path, depending on
+// the device provider.
+var devicePathProperties = []string{"device.path", "api.v4l2.path", "device.string"}
+
+// frameRatePattern matches the frame rates of a caps structure, a single fraction or a list.
+var frameRatePattern = regexp.MustCompile(`framerate=\(fraction\)(\{[^}]*\}|[0-9]+/[0-9]+)`)
+
+// fractionPattern matches the fractions of a frame rate list.
+var fractionPattern = regexp.MustCompile(`([0-9]+)/([0-9]+)`)
+
+// Device is a capture device, e.g. a camera or a microphone, found by ListVideoDevices,
+// ListAudioDevices or a DeviceMonitor.
+type Device struct {
+	Name    string // display name, e.g. "Integrated Camera"
+	Path    string // e.g. "/dev/video0", empty if the device provider doesn't give it
+	Class   string // VideoSourceClass or AudioSourceClass
+	Formats []DeviceFormat
+
+	device *gst.Device
+}
+
+// DeviceFormat is a format that a capture device supports.
+type DeviceFormat struct {
+	Caps       string      // the GStreamer caps of the format
+	MediaType  string      // e.g. "video/x-raw", "image/jpeg" or "audio/x-raw"
+	Width      int         // 0 for the audio formats, or if the device accepts a range of sizes
+	Height     int         // 0 for the audio formats, or if the device accepts a range of sizes
+	FrameRates []FrameRate // empty if the device accepts a range of rates
+}
+
+// FrameRate is a number of frames per second, as the exact fraction of the device caps, e.g.
+// 30000/1001 for 29.97 frames per second.
+type FrameRate struct {
+	Num, Den int
+}
+
+// Float64 returns the number of frames per second, 0 if the denominator is 0.
+func (r FrameRate) Float64() float64 {
+	if r.Den == 0 {
+		return 0
+	}
+	return float64(r.Num) / float64(r.Den)
+}
+
+// String returns the fraction, e.g. "30000/1001".
+func (r FrameRate) String() string {
+	return strconv.Itoa(r.Num) + "/" + strconv.Itoa(r.Den)
+}
+
+// CreateElement creates the source element of the device, configured to capture it.
+func (d Device) CreateElement(name string) (*gst.Element, error) {
+	if d.device == nil {
+		return nil, ErrDeviceNotFound
+	}
+	element := d.device.CreateElement(name)
+	if element == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotFound, d.Name)
+	}
+	return element, nil
+}
+
+// ListVideoDevices returns the cameras and the other video capture devices of the system.
+func ListVideoDevices() []Device {
+	return listDevices(VideoSourceClass)
+}
+
+// ListAudioDevices returns the microphones and the other audio capture devices of the system.
+func ListAudioDevices() []Device {
+	return listDevices(AudioSourceClass)
+}
+
+// listDevices probes the devices of the class.
+func listDevices(class string) []Device {
+	gst.Init(nil) // does nothing if gstreamer is already initialized
+
+	monitor := gst.NewDeviceMonitor()
+	monitor.AddFilter(class, nil)
+	found := monitor.GetDevices()
+	devices := make([]Device, 0, len(found))
+	for _, device := range found {
+		devices = append(devices, newDevice(device))
+	}
+	return devices
+}
+
+// newDevice reads the name, the path and the formats of a GStreamer device.
+func newDevice(device *gst.Device) Device {
+	d := Device{
+		Name:   device.GetDisplayName(),
+		device: device,
+	}
+	switch {
+	case device.HasClasses([]string{"Video", "Source"}):
+		d.Class = VideoSourceClass
+	case device.HasClasses([]string{"Audio", "Source"}):
+		d.Class = AudioSourceClass
+	default:
+		d.Class = device.GetDeviceClass()
+	}
+
+	if properties := device.GetProperties(); properties != nil {
+		for _, name := range devicePathProperties {
+			if value, err := properties.GetValue(name); err == nil {
+				if path, ok := value.(string); ok && path != "" {
+					d.Path = path
+					break
+				}
+			}
+		}
+	}
+
+	if caps := device.GetCaps(); caps != nil {
+		for i := 0; i < caps.GetSize(); i++ {
+			d.Formats = append(d.Formats, newDeviceFormat(caps.GetStructureAt(i)))
+		}
+	}
+	return d
+}
+
+// newDeviceFormat reads the media type, the size and the frame rates of a caps structure. The
+// sizes and the rates that are ranges are left to 0.
+func newDeviceFormat(structure *gst.Structure) DeviceFormat {
+	format := DeviceFormat{
+		Caps:      structure.String(),
+		MediaType: structure.Name(),
+	}
+	if value, err := structure.GetValue("width"); err == nil {
+		format.Width, _ = value.(int)
+	}
+	if value, err := structure.GetValue("height"); err == nil {
+		format.Height, _ = value.(int)
+	}
+	format.FrameRates = parseFrameRates(format.Caps)
+	return format
+}
+
+// parseFrameRates returns the frame rates of a caps structure, given as a fraction or a list of
+// fractions. It is empty if the frame rate is a range.
+func parseFrameRates(caps string) []FrameRate {
+	match := frameRatePattern.FindStringSubmatch(caps)
+	if match == nil {
+		return nil
+	}
+	var rates []FrameRate
+	for _, fraction := range fractionPattern.FindAllStringSubmatch(match[1], -1) {
+		num, _ := strconv.Atoi(fraction[1])
+		den, _ := strconv.Atoi(fraction[2])
+		if den > 0 && num > 0 {
+			rates = append(rates, FrameRate{num, den})
+		}
+	}
+	return rates
+}
+
+// DeviceMonitor calls its callbacks when the capture devices are plugged or unplugged.
+type DeviceMonitor struct {
+	monitor   *gst.DeviceMonitor
+	onAdded   func(Device)
+	onRemoved func(Device)
+	lock      sync.Mutex    // protects the callbacks that are called by the watching goroutine
+	done      chan struct{} // closed by Stop, nil if the monitor is stopped
+}
+
+// NewDeviceMonitor returns a monitor of the devices of the given classes, e.g. VideoSourceClass.
+// All the video and audio capture devices are monitored if no class is given.
+func NewDeviceMonitor(classes ...string) *DeviceMonitor {
+	gst.Init(nil) // does nothing if gstreamer is already initialized
+
+	if len(classes) == 0 {
+		classes = []string{VideoSourceClass, AudioSourceClass}
+	}
+	monitor := gst.NewDeviceMonitor()
+	for _, class := range classes {
+		monitor.AddFilter(class, nil)
+	}
+	return &DeviceMonitor{monitor: monitor}
+}
+
+// Devices returns the monitored devices that are currently plugged.
+func (m *DeviceMonitor) Devices() []Device {
+	found := m.monitor.GetDevices()
+	devices := make([]Device, 0, len(found))
+	for _, device := range found {
+		devices = append(devices, newDevice(device))
+	}
+	return devices
+}
+
+// SetOnDeviceAdded sets the function called when a device is plugged, in another goroutine.
+func (m *DeviceMonitor) SetOnDeviceAdded(f func(Device)) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onAdded = f
+}
+
+// SetOnDeviceRemoved sets the function called when a device is unplugged, in another goroutine.
+func (m *DeviceMonitor) SetOnDeviceRemoved(f func(Device)) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onRemoved = f
+}
+
+// Start starts to watch the devices. The callbacks are not called for the devices that are
+// already plugged, see Devices.
+func (m *DeviceMonitor) Start() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.done != nil {
+		return nil
+	}
+	if !m.monitor.Start() {
+		return ErrDeviceMonitorFailed
+	}
+	m.done = make(chan struct{})
+	go m.watch(m.monitor.GetBus(), m.done)
+	return nil
+}
+
+// Stop stops to watch the devices.
+func (m *DeviceMonitor) Stop() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.done == nil {
+		return
+	}
+	close(m.done)
+	m.done = nil
+	m.monitor.Stop()
+}
+
+// watch calls the callbacks for the messages of the monitor until done is closed. The bus is
+// polled, so that it works without a running GLib main loop.
+func (m *DeviceMonitor) watch(bus *gst.Bus, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		msg := bus.TimedPopFiltered(gst.ClockTime(100*time.Millisecond), gst.MessageDeviceAdded|gst.MessageDeviceRemoved)
+		if msg == nil {
+			continue
+		}
+		m.lock.Lock()
+		onAdded, onRemoved := m.onAdded, m.onRemoved
+		m.lock.Unlock()
+		switch msg.Type() {
+		case gst.MessageDeviceAdded:
+			if onAdded != nil {
+				onAdded(newDevice(msg.ParseDeviceAdded()))
+			}
+		case gst.MessageDeviceRemoved:
+			if onRemoved != nil {
+				onRemoved(newDevice(msg.ParseDeviceRemoved()))
+			}
+		}
+	}
+}
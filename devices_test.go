@@ -0,0 +1,63 @@
+package streamer
+
+import (
+	"testing"
+
+	"github.com/go-gst/go-gst/gst"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFrameRates(t *testing.T) {
+	assert.Equal(t, []FrameRate{{30, 1}}, parseFrameRates("video/x-raw, width=(int)640, framerate=(fraction)30/1"))
+	assert.Equal(t, []FrameRate{{30, 1}, {15, 1}, {30000, 1001}},
+		parseFrameRates("image/jpeg, framerate=(fraction){ 30/1, 15/1, 30000/1001 }"))
+	assert.Empty(t, parseFrameRates("video/x-raw, framerate=(fraction)[ 0/1, 2147483647/1 ]"))
+	assert.Empty(t, parseFrameRates("audio/x-raw, rate=(int)48000"))
+}
+
+func TestFrameRate(t *testing.T) {
+	rate := FrameRate{30000, 1001}
+	assert.Equal(t, "30000/1001", rate.String())
+	assert.InDelta(t, 29.97, rate.Float64(), 0.001)
+	assert.Equal(t, float64(0), FrameRate{}.Float64())
+}
+
+func TestNewDeviceFormat(t *testing.T) {
+	gst.Init(nil)
+	caps := gst.NewCapsFromString("video/x-raw,format=YUY2,width=640,height=480,framerate={30/1,15/1};image/jpeg,width=[1,1920],height=[1,1080]")
+	assert.Equal(t, 2, caps.GetSize())
+
+	format := newDeviceFormat(caps.GetStructureAt(0))
+	assert.Equal(t, "video/x-raw", format.MediaType)
+	assert.Equal(t, 640, format.Width)
+	assert.Equal(t, 480, format.Height)
+	assert.Equal(t, []FrameRate{{30, 1}, {15, 1}}, format.FrameRates)
+
+	// the ranges are not fixed sizes
+	format = newDeviceFormat(caps.GetStructureAt(1))
+	assert.Equal(t, "image/jpeg", format.MediaType)
+	assert.Equal(t, 0, format.Width)
+	assert.Empty(t, format.FrameRates)
+}
+
+func TestListDevices(t *testing.T) {
+	// the test machines may have no capture device, but the listed ones must be complete
+	for _, device := range ListVideoDevices() {
+		assert.Equal(t, VideoSourceClass, device.Class)
+		assert.NotEmpty(t, device.Name)
+		element, err := device.CreateElement("")
+		assert.Nil(t, err)
+		assert.NotNil(t, element)
+	}
+	for _, device := range ListAudioDevices() {
+		assert.Equal(t, AudioSourceClass, device.Class)
+	}
+
+	monitor := NewDeviceMonitor(VideoSourceClass)
+	monitor.SetOnDeviceAdded(func(Device) {})
+	assert.Len(t, monitor.Devices(), len(ListVideoDevices()))
+	if err := monitor.Start(); err == nil {
+		monitor.Stop()
+	}
+	monitor.Stop() // stopping twice does nothing
+}
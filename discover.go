@@ -0,0 +1,158 @@
+package streamer
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/go-gst/go-gst/gst"
+	"github.com/go-gst/go-gst/gst/pbutils"
+)
+
+// DefaultDiscoverTimeout is the timeout of Discover when 0 is given.
+const DefaultDiscoverTimeout = 5 * time.Second
+
+// MediaInfo is the information of a media found by Discover, without playing it.
+type MediaInfo struct {
+	URI       fyne.URI
+	Duration  time.Duration // 0 for live streams or if unknown
+	Seekable  bool
+	Live      bool
+	Container string // container format, e.g. "Matroska", empty if there is no container
+	Metadata  Metadata
+
+	Video     []VideoStreamInfo
+	Audio     []AudioStreamInfo
+	Subtitles []SubtitleStreamInfo
+}
+
+// VideoStreamInfo is the information of a video stream of a media.
+type VideoStreamInfo struct {
+	Codec            string
+	Width            int
+	Height           int
+	FrameRate        float64 // frames per second, 0 if unknown or variable
+	PixelAspectRatio float64 // 1 for square pixels
+	Bitrate          uint    // in bits per second, 0 if unknown
+	Image            bool    // true if the stream is a still image
+}
+
+// AudioStreamInfo is the information of an audio stream of a media.
+type AudioStreamInfo struct {
+	Codec      string
+	Language   string // ISO 639 language code, e.g. "en", empty if unknown
+	Channels   int
+	SampleRate int  // in Hz
+	Bitrate    uint // in bits per second, 0 if unknown
+}
+
+// SubtitleStreamInfo is the information of a subtitle stream of a media.
+type SubtitleStreamInfo struct {
+	Codec    string
+	Language string // ISO 639 language code, e.g. "en", empty if unknown
+}
+
+// Discover reads the information of the media at the given location (duration, streams, tags...)
+// without playing it, so it is cheap enough to list a folder of files. It blocks until the
+// information is found or the timeout is reached, DefaultDiscoverTimeout is used if timeout is 0.
+func Discover(uri fyne.URI, timeout time.Duration) (*MediaInfo, error) {
+	if timeout <= 0 {
+		timeout = DefaultDiscoverTimeout
+	}
+	gst.Init(nil) // does nothing if gstreamer is already initialized
+
+	discoverer, err := pbutils.NewDiscoverer(gst.ClockTime(timeout))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscoveryFailed, err)
+	}
+	info, err := discoverer.DiscoverURI(uri.String())
+	if err != nil || info == nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscoveryFailed, err)
+	}
+	switch result := info.GetResult(); result {
+	case pbutils.DiscovererResultOK:
+		// the information is complete
+	case pbutils.DiscovererResultTimeout:
+		return nil, ErrDiscoveryTimeout
+	default:
+		return nil, fmt.Errorf("%w: result %d", ErrDiscoveryFailed, result)
+	}
+
+	media := &MediaInfo{
+		URI:      uri,
+		Seekable: info.GetSeekable(),
+		Live:     info.GetLive(),
+	}
+	if duration := info.GetDuration().AsDuration(); duration != nil && !media.Live {
+		media.Duration = *duration
+	}
+	media.Metadata.AddTags(info.GetTags())
+	media.Container = media.Metadata.Container
+	containers := info.GetContainerStreams()
+	for _, container := range containers {
+		keepStreamInfo(container.DiscovererStreamInfo)
+	}
+	if len(containers) > 0 && media.Container == "" {
+		media.Container = streamCodec(containers[0].DiscovererStreamInfo, gst.TagContainerFormat)
+	}
+
+	for _, stream := range info.GetVideoStreams() {
+		keepStreamInfo(stream.DiscovererStreamInfo)
+		video := VideoStreamInfo{
+			Codec:            streamCodec(stream.DiscovererStreamInfo, gst.TagVideoCodec),
+			Width:            int(stream.GetWidth()),
+			Height:           int(stream.GetHeight()),
+			PixelAspectRatio: 1,
+			Bitrate:          stream.GetBitrate(),
+			Image:            stream.IsImage(),
+		}
+		if num, denom := stream.GetFramerateNum(), stream.GetFramerateDenom(); num > 0 && denom > 0 {
+			video.FrameRate = float64(num) / float64(denom)
+		}
+		if num, denom := stream.GetPARNum(), stream.GetPARDenom(); num > 0 && denom > 0 {
+			video.PixelAspectRatio = float64(num) / float64(denom)
+		}
+		media.Video = append(media.Video, video)
+	}
+
+	for _, stream := range info.GetAudioStreams() {
+		keepStreamInfo(stream.DiscovererStreamInfo)
+		media.Audio = append(media.Audio, AudioStreamInfo{
+			Codec:      streamCodec(stream.DiscovererStreamInfo, gst.TagAudioCodec),
+			Language:   stream.GetLanguage(),
+			Channels:   int(stream.GetChannels()),
+			SampleRate: int(stream.GetSampleRate()),
+			Bitrate:    stream.GetBitate(),
+		})
+	}
+
+	for _, stream := range info.GetSubtitleStreams() {
+		keepStreamInfo(stream.DiscovererStreamInfo)
+		media.Subtitles = append(media.Subtitles, SubtitleStreamInfo{
+			Codec:    streamCodec(stream.DiscovererStreamInfo, gst.TagSubtitleCodec),
+			Language: stream.GetLanguage(),
+		})
+	}
+
+	return media, nil
+}
+
+// keepStreamInfo adds the reference that the go-gst stream lists release too early: the lists
+// are freed with their references, but the returned objects also release them when collected.
+func keepStreamInfo(stream *pbutils.DiscovererStreamInfo) {
+	stream.Ref()
+}
+
+// streamCodec returns the codec of the stream from its tags, or the name of its caps if there
+// is no tag, e.g. "video/x-vp8".
+func streamCodec(stream *pbutils.DiscovererStreamInfo, tag gst.Tag) string {
+	if tags := stream.GetTags(); tags != nil {
+		if codec, ok := tags.GetString(tag); ok && codec != "" {
+			return codec
+		}
+	}
+	if caps := stream.GetCaps(); caps != nil && caps.GetSize() > 0 {
+		return caps.GetStructureAt(0).Name()
+	}
+	return ""
+}
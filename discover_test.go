@@ -0,0 +1,30 @@
+package streamer
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	path, err := filepath.Abs(filepath.Join("video", "test-files", "testvideo.ogv"))
+	assert.Nil(t, err)
+
+	info, err := Discover(storage.NewFileURI(path), 0)
+	assert.Nil(t, err)
+	assert.NotNil(t, info)
+	assert.True(t, info.Duration > 0)
+	assert.True(t, info.Seekable)
+	assert.False(t, info.Live)
+	assert.NotEmpty(t, info.Container)
+	assert.Len(t, info.Video, 1)
+	assert.True(t, info.Video[0].Width > 0)
+	assert.True(t, info.Video[0].Height > 0)
+	assert.NotEmpty(t, info.Video[0].Codec)
+
+	_, err = Discover(storage.NewFileURI(filepath.Join(t.TempDir(), "missing.ogv")), 0)
+	assert.True(t, errors.Is(err, ErrDiscoveryFailed))
+}
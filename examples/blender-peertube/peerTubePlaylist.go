@@ -13,6 +13,7 @@ import (
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
+	"github.com/metal3d/fyne-streamer/playlist"
 	"github.com/metal3d/fyne-streamer/video"
 )
 
@@ -65,36 +66,46 @@ func (v *videoListElement) SetVideoInfo(video *peerTubeVideo) {
 func getPeerTubeList(videoWidget *video.Player, w fyne.Window) *widget.List {
 	// get some videos from Blender PeerTube instance
 	videos := getPeerTubeVideos()
-	videotitles := func() []string {
-		titles := make([]string, len(videos))
-		i := 0
-		for k := range videos {
-			titles[i] = k
-			i++
+
+	// the playlist plays the next video at the end of the current one
+	entries := playlist.New()
+	entries.SetRepeat(playlist.RepeatAll)
+	for title, video := range videos {
+		u, err := storage.ParseURI(video.VideoURL)
+		if err != nil {
+			fyne.LogError("Invalid video URL", err)
+			continue
 		}
-		return titles
-	}()
+		entries.AddEntries(playlist.Entry{URI: u, Title: title})
+	}
+	videoWidget.SetPlaylist(entries)
 
 	list := widget.NewList(func() int {
-		return len(videos)
+		return entries.Len()
 	}, func() fyne.CanvasObject {
 		return newVideoListElement()
 	}, func(id widget.ListItemID, element fyne.CanvasObject) {
 		e := element.(*videoListElement)
-		video := videos[videotitles[id]]
+		entry, err := entries.Entry(id)
+		if err != nil {
+			return
+		}
+		video := videos[entry.Title]
 		e.SetVideoInfo(&video)
 	})
 	list.OnSelected = func(id widget.ListItemID) {
+		if id == entries.Current() {
+			return // selected by the playlist
+		}
 		videoWidget.Pause()
-		u, err := storage.ParseURI(videos[videotitles[id]].VideoURL)
-		if err != nil {
+		if err := entries.SetCurrent(id); err != nil {
 			dialog.ShowError(err, w)
-			return
 		}
-		w.SetTitle(videotitles[id])
-		videoWidget.Open(u)
-		videoWidget.Play()
 	}
+	entries.SetOnCurrentChanged(func(index int, entry playlist.Entry) {
+		w.SetTitle(entry.Name())
+		list.Select(index)
+	})
 
 	return list
 }
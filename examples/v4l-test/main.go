@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"runtime"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -13,44 +12,121 @@ import (
 	"github.com/metal3d/fyne-streamer/video"
 )
 
+// cameraFormat is a size and a frame rate that a camera supports.
+type cameraFormat struct {
+	width, height int
+	frameRate     streamer.FrameRate
+}
+
+func (f cameraFormat) String() string {
+	if f.frameRate.Den == 0 {
+		return fmt.Sprintf("%dx%d", f.width, f.height)
+	}
+	return fmt.Sprintf("%dx%d @ %.4g fps", f.width, f.height, f.frameRate.Float64())
+}
+
+// cameraFormats returns the sizes and the frame rates of the camera, without duplicates. The
+// first entry lets the camera choose.
+func cameraFormats(device streamer.Device) []cameraFormat {
+	formats := []cameraFormat{{}}
+	seen := map[cameraFormat]bool{}
+	for _, format := range device.Formats {
+		if format.Width == 0 || format.Height == 0 {
+			continue
+		}
+		rates := format.FrameRates
+		if len(rates) == 0 {
+			rates = []streamer.FrameRate{{}}
+		}
+		for _, rate := range rates {
+			f := cameraFormat{format.Width, format.Height, rate}
+			if !seen[f] {
+				seen[f] = true
+				formats = append(formats, f)
+			}
+		}
+	}
+	return formats
+}
+
 func main() {
 	a := app.New()
 	w := a.NewWindow("Camera with Ripple Effect")
 
-	var source string
-	switch runtime.GOOS {
-	case "linux":
-		source = "v4l2src"
-	case "darwin":
-		source = "avfvideosrc"
-	case "windows":
-		source = "ksvideosrc"
-	default:
-		panic("unsupported platform")
+	viewer := video.NewViewer()
+	var devices []streamer.Device
+	var formats []cameraFormat
+	var device streamer.Device
+
+	open := func(format cameraFormat) {
+		u, err := video.DeviceURI(device, format.width, format.height, format.frameRate)
+		if err != nil {
+			fyne.LogError("Failed to get the camera location", err)
+			return
+		}
+		if err := viewer.Open(u); err != nil {
+			fyne.LogError("Failed to open the camera", err)
+			return
+		}
+		viewer.Play()
 	}
 
-	pipeline := `
-    # source element is v4l2src on linux, avfvideosrc on darwin and ksvideosrc on windows
-    %s name=cam !
-    videoconvert name=convert !
-    videoscale !
-    videoflip video-direction=horiz name=direction ! # flip video horizontally
-    # <- this is where we will add the ripple effect
-    jpegenc name={{ .ImageEncoderElementName }} !
-    appsink name={{ .AppSinkElementName }}
-    `
-	viewer := video.NewViewer()
-	err := viewer.SetPipelineFromString(fmt.Sprintf(pipeline, source))
-	if err != nil {
-		panic(err)
+	formatSelect := widget.NewSelect(nil, func(string) {})
+	formatSelect.OnChanged = func(string) {
+		if i := formatSelect.SelectedIndex(); i >= 0 {
+			open(formats[i])
+		}
+	}
+
+	deviceSelect := widget.NewSelect(nil, func(string) {})
+	deviceSelect.OnChanged = func(string) {
+		i := deviceSelect.SelectedIndex()
+		if i < 0 {
+			return
+		}
+		device = devices[i]
+		formats = cameraFormats(device)
+		options := make([]string, len(formats))
+		for i, format := range formats {
+			options[i] = format.String()
+		}
+		options[0] = "Default format"
+		formatSelect.ClearSelected()
+		formatSelect.Options = options
+		formatSelect.SetSelectedIndex(0) // opens the camera
+	}
+
+	// the list of cameras follows the plugged devices
+	monitor := streamer.NewDeviceMonitor(streamer.VideoSourceClass)
+	refresh := func(streamer.Device) {
+		devices = monitor.Devices()
+		names := make([]string, len(devices))
+		for i, device := range devices {
+			names[i] = device.Name
+		}
+		deviceSelect.Options = names
+		deviceSelect.Refresh()
+	}
+	monitor.SetOnDeviceAdded(refresh)
+	monitor.SetOnDeviceRemoved(refresh)
+	if err := monitor.Start(); err != nil {
+		fyne.LogError("Failed to watch the cameras", err)
+	}
+	defer monitor.Stop()
+	refresh(streamer.Device{})
+	if len(devices) > 0 {
+		deviceSelect.SetSelectedIndex(0)
 	}
-	viewer.Play()
 
 	rippleButton := widget.NewButton("Toggle Ripple", func() {
 		toggleRippleFilter(viewer.Pipeline())
 	})
 
-	w.SetContent(container.NewBorder(nil, rippleButton, nil, nil, viewer))
+	w.SetContent(container.NewBorder(
+		container.NewGridWithColumns(2, deviceSelect, formatSelect),
+		rippleButton, nil, nil,
+		viewer,
+	))
 
 	w.Resize(fyne.NewSize(640, 480))
 	w.ShowAndRun()
@@ -58,28 +134,30 @@ func main() {
 
 // This is a simple example of how to add a filter to a pipeline.
 // We will add a ripple effect to the video stream between the
-// videoflip and jpegenc elements. Or remove it if it is already there.
+// videorate and videobalance elements. Or remove it if it is already there.
 func toggleRippleFilter(pipeline *gst.Pipeline) {
 	if pipeline == nil {
 		return
 	}
-	videoflip, _ := pipeline.GetElementByName("direction")
-	encoder, _ := pipeline.GetElementByName(streamer.ImageEncoderElementName)
+	videorate, _ := pipeline.GetElementByName(streamer.VideoRateElementName)
+	balance, _ := pipeline.GetElementByName(streamer.VideoBalanceElementName)
 	ripple, err := pipeline.GetElementByName("ripples")
 
 	if err != nil || ripple == nil {
-		// create a ripple effect and place it between videoflip and jpegenc
-		ripple, _ := gst.NewElementWithName("rippletv", "ripples")
-		pipeline.Add(ripple)                // add ripple to pipeline
-		videoflip.Unlink(encoder)           // disconnect videoflip from encoder
-		videoflip.Link(ripple)              // connect videoflip to ripple
-		ripple.Link(encoder)                // and ripple to encoder
+		// create a ripple effect, with the conversions of its format, and place it between
+		// videorate and videobalance
+		ripple, _ := gst.NewBinFromString("videoconvert ! rippletv ! videoconvert", true)
+		ripple.SetProperty("name", "ripples")
+		pipeline.Add(ripple.Element)        // add ripple to pipeline
+		videorate.Unlink(balance)           // disconnect videorate from videobalance
+		videorate.Link(ripple.Element)      // connect videorate to ripple
+		ripple.Link(balance)                // and ripple to videobalance
 		pipeline.SetState(gst.StatePlaying) // let's go
 	} else {
 		// ripple already exists, remove it
 		ripple.SetState(gst.StateNull)      // stop ripple before remove it
 		pipeline.Remove(ripple)             // remove ripple from pipeline
-		videoflip.Link(encoder)             // connect videoscale to encoder
+		videorate.Link(balance)             // connect videorate to videobalance
 		pipeline.SetState(gst.StatePlaying) // let's go
 	}
 }
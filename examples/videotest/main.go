@@ -11,8 +11,8 @@ func main() {
     videotestsrc name={{ .InputElementName }} ! # the input, a video test
     videoconvert n-threads=4 ! # convert to something usable
     videorate name={{ .VideoRateElementName }} max-rate=30 ! # fix the framerate
-    # encode to jpeg (or png), mandatory for appsink
-    jpegenc name={{ .ImageEncoderElementName }} quality=80 !
+    # raw RGBA frames for the appsink
+    video/x-raw,format=RGBA !
     # the appsink (mandatory)
     appsink name={{ .AppSinkElementName }} drop=true max-lateness=33333 sync=true
     `
@@ -10,7 +10,8 @@ const (
 	DecodeElementName ElementName = "fyne-decode"
 
 	// AppSinkElementName is the name of the appsink element. It's the mandatory element
-	// in the pipeline.
+	// in the pipeline. It should receive raw "video/x-raw,format=RGBA" frames, or
+	// jpeg/png images in legacy mode (see ImageEncoderElementName).
 	AppSinkElementName ElementName = "fyne-app"
 
 	// VideoRateElementName is the name of the videorate element. It's used to limit
@@ -23,6 +24,9 @@ const (
 	// the appsink element.
 	// Generally, it's a "jpenenc". But it can be a "pngenc" if you want to encode the frames
 	// with alpha channel.
+	//
+	// This is the legacy mode, the default pipelines now send raw RGBA frames to the appsink
+	// that are not encoded and decoded for each frame.
 	ImageEncoderElementName ElementName = "fyne-imageencoder"
 
 	// VolumeElementName is the name of the volume element.
@@ -32,6 +36,38 @@ const (
 	// VideoBalanceElementName is the name of the videobalance element. It can be used to
 	// controle the brightness, contrast, hue and saturation of the video.
 	VideoBalanceElementName ElementName = "fyne-videobalance"
+
+	// SpectrumElementName is the name of the spectrum element. It posts the magnitudes
+	// of the frequency bands of the audio on the bus, to draw a visualizer.
+	// Place it in the audio branch, before the volume element.
+	SpectrumElementName ElementName = "fyne-spectrum"
+
+	// LevelElementName is the name of the level element. It posts the RMS and peak levels
+	// of each audio channel on the bus, to draw a VU meter. Place it just after the volume element.
+	LevelElementName ElementName = "fyne-level"
+
+	// AudioSelectorElementName is the name of the input-selector element that receives the audio
+	// tracks of the decode element. It is used to switch the audio track (e.g. the language).
+	// Place it at the beginning of the audio branch, the tracks are linked to it by the viewer.
+	AudioSelectorElementName ElementName = "fyne-audioselector"
+
+	// SubtitleSelectorElementName is the name of the input-selector element that receives the text
+	// subtitle tracks of the decode element. The tracks are linked to it by the viewer.
+	SubtitleSelectorElementName ElementName = "fyne-subtitleselector"
+
+	// SubtitleSinkElementName is the name of the appsink element that receives the text of the selected
+	// subtitle track, to display it over the frame. Place it just after the subtitle selector.
+	SubtitleSinkElementName ElementName = "fyne-subtitlesink"
+)
+
+// RawVideoFormat is the raw video format that the appsink element can receive without
+// any image encoder. Use "video/x-raw,format=RGBA" caps just before the appsink element.
+const RawVideoFormat = "RGBA"
+
+// Limits of the playback rate, in absolute value. Negative rates play the stream backward.
+const (
+	MinPlaybackRate = 0.25
+	MaxPlaybackRate = 4.0
 )
 
 // TimeFormat is the format used to display the time in the video widget.
@@ -40,13 +76,18 @@ const TimeFormat = "15:04:05"
 // ElementMap is a map of the element names used in the pipeline. This is used
 // in templates to create the pipeline.
 var ElementMap = map[string]ElementName{
-	"InputElementName":        InputElementName,
-	"DecodeElementName":       DecodeElementName,
-	"VideoRateElementName":    VideoRateElementName,
-	"ImageEncoderElementName": ImageEncoderElementName,
-	"AppSinkElementName":      AppSinkElementName,
-	"VolumeElementName":       VolumeElementName,
-	"VideoBalanceElementName": VideoBalanceElementName,
+	"InputElementName":            InputElementName,
+	"DecodeElementName":           DecodeElementName,
+	"VideoRateElementName":        VideoRateElementName,
+	"ImageEncoderElementName":     ImageEncoderElementName,
+	"AppSinkElementName":          AppSinkElementName,
+	"VolumeElementName":           VolumeElementName,
+	"VideoBalanceElementName":     VideoBalanceElementName,
+	"SpectrumElementName":         SpectrumElementName,
+	"LevelElementName":            LevelElementName,
+	"AudioSelectorElementName":    AudioSelectorElementName,
+	"SubtitleSelectorElementName": SubtitleSelectorElementName,
+	"SubtitleSinkElementName":     SubtitleSinkElementName,
 }
 
 // ElementName is the name of a GStreamer element. It's a string (alias).
@@ -59,4 +100,35 @@ var (
 	ErrSeekFailed            = fmt.Errorf("seek failed")
 	ErrFailedToGetFirstFrame = fmt.Errorf("failed to get the first frame")
 	ErrNoDuration            = fmt.Errorf("couldn't get duration")
+	ErrUnsupportedRawFormat  = fmt.Errorf("unsupported raw video format")
+	ErrStepFailed            = fmt.Errorf("frame step failed")
+	ErrNoFrameRate           = fmt.Errorf("couldn't get the frame rate")
+	ErrInvalidPlaybackRate   = fmt.Errorf("invalid playback rate")
+	ErrNoFrame               = fmt.Errorf("no frame to display")
+	ErrNoLocation            = fmt.Errorf("no media location, the pipeline is not opened from an URI")
+	ErrUnsupportedImageType  = fmt.Errorf("unsupported image format")
+	ErrNoSpectrum            = fmt.Errorf("no spectrum element in the pipeline")
+	ErrInvalidSpectrum       = fmt.Errorf("invalid spectrum settings")
+	ErrInvalidTrack          = fmt.Errorf("invalid track")
+	ErrNoTrackSelector       = fmt.Errorf("no track selector in the pipeline")
+	ErrInvalidSubtitles      = fmt.Errorf("invalid subtitles")
+	ErrInvalidChapter        = fmt.Errorf("invalid chapter")
+	ErrDiscoveryFailed       = fmt.Errorf("media discovery failed")
+	ErrDiscoveryTimeout      = fmt.Errorf("media discovery timed out")
+	ErrInvalidEntry          = fmt.Errorf("invalid playlist entry")
+	ErrEndOfPlaylist         = fmt.Errorf("no more entries in the playlist")
+	ErrNoPlayer              = fmt.Errorf("no player attached to the playlist")
+	ErrUnknownPlaylistFormat = fmt.Errorf("unknown playlist format")
+	ErrInvalidPlaylist       = fmt.Errorf("invalid playlist file")
+	ErrNotAdaptive           = fmt.Errorf("the stream is not an adaptive stream with variants")
+	ErrInvalidVariant        = fmt.Errorf("invalid variant")
+	ErrInvalidAddress        = fmt.Errorf("invalid network address")
+	ErrUnsupportedEncoding   = fmt.Errorf("unsupported RTP encoding")
+	ErrDeviceNotFound        = fmt.Errorf("capture device not found")
+	ErrDeviceMonitorFailed   = fmt.Errorf("failed to start the device monitor")
+	ErrAlreadyRecording      = fmt.Errorf("the stream is already recorded")
+	ErrNotRecording          = fmt.Errorf("the stream is not recorded")
+	ErrNoRecordEncoder       = fmt.Errorf("no encoder for the recording format")
+	ErrRecordingFailed       = fmt.Errorf("recording failed")
+	ErrUnsupportedScheme     = fmt.Errorf("unsupported location scheme")
 )
@@ -0,0 +1,102 @@
+// Package adaptive detects the HLS and DASH adaptive streams and reads the variants of their
+// manifests.
+package adaptive
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// fetchTimeout is the maximum time to download a manifest.
+const fetchTimeout = 10 * time.Second
+
+// Kind is the protocol of an adaptive stream.
+type Kind int
+
+const (
+	// None is not an adaptive stream.
+	None Kind = iota
+
+	// HLS is the HTTP Live Streaming protocol, with a ".m3u8" manifest.
+	HLS
+
+	// DASH is the Dynamic Adaptive Streaming over HTTP protocol, with a ".mpd" manifest.
+	DASH
+)
+
+// Variant is a version of the stream at a given bitrate, e.g. a resolution of the video.
+type Variant struct {
+	Bandwidth uint // in bits per second
+	Width     int  // 0 if unknown or for audio only variants
+	Height    int
+	Codecs    string // RFC 6381 codecs, e.g. "avc1.4d401f,mp4a.40.2", empty if unknown
+}
+
+// KindOf returns the kind of adaptive stream of the http(s) location, from its extension or
+// its MIME type.
+func KindOf(u fyne.URI) Kind {
+	if u.Scheme() != "http" && u.Scheme() != "https" {
+		return None
+	}
+	extension := strings.ToLower(u.Extension())
+	mimeType := strings.ToLower(u.MimeType())
+	switch {
+	case extension == ".m3u8",
+		mimeType == "application/vnd.apple.mpegurl",
+		mimeType == "application/x-mpegurl":
+		return HLS
+	case extension == ".mpd",
+		mimeType == "application/dash+xml":
+		return DASH
+	default:
+		return None
+	}
+}
+
+// Fetch downloads the manifest of the adaptive stream and returns its variants, sorted by
+// bandwidth. A HLS media playlist has no variant.
+func Fetch(u fyne.URI, kind Kind) ([]Variant, error) {
+	client := http.Client{Timeout: fetchTimeout}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the manifest: %s", resp.Status)
+	}
+	return Parse(resp.Body, kind)
+}
+
+// Parse reads the variants of a manifest, sorted by bandwidth.
+func Parse(r io.Reader, kind Kind) ([]Variant, error) {
+	var variants []Variant
+	var err error
+	switch kind {
+	case HLS:
+		variants, err = parseHLS(r)
+	case DASH:
+		variants, err = parseDASH(r)
+	default:
+		return nil, fmt.Errorf("not an adaptive stream")
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(variants, func(i, j int) bool {
+		return variants[i].Bandwidth < variants[j].Bandwidth
+	})
+	return variants, nil
+}
+
+// parseResolution reads a "WIDTHxHEIGHT" resolution.
+func parseResolution(resolution string) (width, height int) {
+	fmt.Sscanf(strings.ToLower(resolution), "%dx%d", &width, &height)
+	return width, height
+}
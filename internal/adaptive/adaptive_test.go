@@ -0,0 +1,80 @@
+package adaptive
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+const hlsMaster = `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-STREAM-INF:BANDWIDTH=2560000,RESOLUTION=1280x720,CODECS="avc1.4d401f,mp4a.40.2"
+high/playlist.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=640000,RESOLUTION=640x360,CODECS="avc1.42e01e,mp4a.40.2"
+low/playlist.m3u8
+`
+
+const dashManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" type="static">
+  <Period>
+    <AdaptationSet mimeType="video/mp4" codecs="avc1.4d401f">
+      <Representation id="1" bandwidth="3000000" width="1920" height="1080"/>
+      <Representation id="2" bandwidth="1000000" width="960" height="540" codecs="avc1.42e01e"/>
+    </AdaptationSet>
+    <AdaptationSet contentType="audio" mimeType="audio/mp4">
+      <Representation id="3" bandwidth="128000"/>
+    </AdaptationSet>
+  </Period>
+</MPD>
+`
+
+func TestKindOf(t *testing.T) {
+	hls, _ := storage.ParseURI("https://example.com/live/master.m3u8")
+	dash, _ := storage.ParseURI("http://example.com/movie/manifest.mpd")
+	video, _ := storage.ParseURI("http://example.com/movie.webm")
+	assert.Equal(t, HLS, KindOf(hls))
+	assert.Equal(t, DASH, KindOf(dash))
+	assert.Equal(t, None, KindOf(video))
+	assert.Equal(t, None, KindOf(storage.NewFileURI("/tmp/master.m3u8")))
+}
+
+func TestParseHLS(t *testing.T) {
+	variants, err := Parse(strings.NewReader(hlsMaster), HLS)
+	assert.Nil(t, err)
+	assert.Equal(t, []Variant{
+		{Bandwidth: 640000, Width: 640, Height: 360, Codecs: "avc1.42e01e,mp4a.40.2"},
+		{Bandwidth: 2560000, Width: 1280, Height: 720, Codecs: "avc1.4d401f,mp4a.40.2"},
+	}, variants)
+
+	// a media playlist has no variant
+	variants, err = Parse(strings.NewReader("#EXTM3U\n#EXT-X-TARGETDURATION:2\n#EXTINF:2,\nsegment0.ts\n"), HLS)
+	assert.Nil(t, err)
+	assert.Empty(t, variants)
+}
+
+func TestParseDASH(t *testing.T) {
+	variants, err := Parse(strings.NewReader(dashManifest), DASH)
+	assert.Nil(t, err)
+	assert.Equal(t, []Variant{
+		{Bandwidth: 1000000, Width: 960, Height: 540, Codecs: "avc1.42e01e"},
+		{Bandwidth: 3000000, Width: 1920, Height: 1080, Codecs: "avc1.4d401f"},
+	}, variants)
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		w.Write([]byte(hlsMaster))
+	}))
+	defer server.Close()
+
+	u, err := storage.ParseURI(server.URL + "/master.m3u8")
+	assert.Nil(t, err)
+	variants, err := Fetch(u, HLS)
+	assert.Nil(t, err)
+	assert.Len(t, variants, 2)
+}
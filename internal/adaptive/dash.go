@@ -0,0 +1,72 @@
+package adaptive
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// dashMPD is the root element of a DASH manifest. Only the representations are read.
+type dashMPD struct {
+	Periods []struct {
+		AdaptationSets []dashAdaptationSet `xml:"AdaptationSet"`
+	} `xml:"Period"`
+}
+
+// dashAdaptationSet is a set of interchangeable representations, e.g. the resolutions of a video.
+type dashAdaptationSet struct {
+	ContentType     string               `xml:"contentType,attr"`
+	MimeType        string               `xml:"mimeType,attr"`
+	Codecs          string               `xml:"codecs,attr"`
+	Representations []dashRepresentation `xml:"Representation"`
+}
+
+// dashRepresentation is a variant of an adaptation set. The attributes that are not set are
+// inherited from the adaptation set.
+type dashRepresentation struct {
+	Bandwidth uint   `xml:"bandwidth,attr"`
+	Width     int    `xml:"width,attr"`
+	Height    int    `xml:"height,attr"`
+	MimeType  string `xml:"mimeType,attr"`
+	Codecs    string `xml:"codecs,attr"`
+}
+
+// parseDASH reads the video representations of the first period of a DASH manifest, or the
+// audio ones if there is no video.
+func parseDASH(r io.Reader) ([]Variant, error) {
+	var mpd dashMPD
+	if err := xml.NewDecoder(r).Decode(&mpd); err != nil {
+		return nil, err
+	}
+	if len(mpd.Periods) == 0 {
+		return nil, nil
+	}
+
+	variants := map[string][]Variant{}
+	for _, set := range mpd.Periods[0].AdaptationSets {
+		for _, representation := range set.Representations {
+			contentType := set.ContentType
+			if contentType == "" {
+				mimeType := representation.MimeType
+				if mimeType == "" {
+					mimeType = set.MimeType
+				}
+				contentType = strings.SplitN(mimeType, "/", 2)[0]
+			}
+			codecs := representation.Codecs
+			if codecs == "" {
+				codecs = set.Codecs
+			}
+			variants[contentType] = append(variants[contentType], Variant{
+				Bandwidth: representation.Bandwidth,
+				Width:     representation.Width,
+				Height:    representation.Height,
+				Codecs:    codecs,
+			})
+		}
+	}
+	if video := variants["video"]; len(video) > 0 {
+		return video, nil
+	}
+	return variants["audio"], nil
+}
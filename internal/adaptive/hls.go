@@ -0,0 +1,67 @@
+package adaptive
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// hlsStreamInfo describes the next variant of a HLS master playlist, e.g.
+// "#EXT-X-STREAM-INF:BANDWIDTH=1280000,RESOLUTION=1280x720,CODECS="avc1.4d401f,mp4a.40.2"".
+const hlsStreamInfo = "#EXT-X-STREAM-INF:"
+
+// parseHLS reads the variants of a HLS master playlist. The media playlists, that have the
+// segments, have no variant.
+func parseHLS(r io.Reader) ([]Variant, error) {
+	var variants []Variant
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, hlsStreamInfo) {
+			continue
+		}
+		attributes := hlsAttributes(strings.TrimPrefix(line, hlsStreamInfo))
+		var variant Variant
+		if bandwidth, err := strconv.ParseUint(attributes["BANDWIDTH"], 10, 64); err == nil {
+			variant.Bandwidth = uint(bandwidth)
+		}
+		variant.Width, variant.Height = parseResolution(attributes["RESOLUTION"])
+		variant.Codecs = attributes["CODECS"]
+		variants = append(variants, variant)
+	}
+	return variants, scanner.Err()
+}
+
+// hlsAttributes reads an attribute list, e.g. `BANDWIDTH=1280000,CODECS="avc1.4d401f,mp4a.40.2"`.
+// The quoted values can have commas, the quotes are removed.
+func hlsAttributes(list string) map[string]string {
+	attributes := map[string]string{}
+	for list != "" {
+		equal := strings.Index(list, "=")
+		if equal < 0 {
+			break
+		}
+		name := strings.TrimSpace(list[:equal])
+		list = list[equal+1:]
+
+		var value string
+		if strings.HasPrefix(list, `"`) {
+			end := strings.Index(list[1:], `"`)
+			if end < 0 {
+				end = len(list) - 1
+			}
+			value, list = list[1:end+1], list[end+1:]
+			list = strings.TrimPrefix(list, `"`)
+		} else {
+			end := strings.Index(list, ",")
+			if end < 0 {
+				end = len(list)
+			}
+			value, list = list[:end], list[end:]
+		}
+		attributes[name] = value
+		list = strings.TrimPrefix(list, ",")
+	}
+	return attributes
+}
@@ -0,0 +1,145 @@
+// Package grabber extracts frames from a media in a hidden pipeline, without any
+// widget nor audio output.
+package grabber
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/go-gst/go-gst/gst"
+	"github.com/go-gst/go-gst/gst/app"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+)
+
+// DefaultTimeout is the time to wait for a frame before giving up.
+const DefaultTimeout = 5 * time.Second
+
+// Grabber is a paused pipeline that is seeked to get frames at given positions.
+type Grabber struct {
+	pipeline *gst.Pipeline
+	appSink  *app.Sink
+	timeout  time.Duration
+}
+
+// New creates a Grabber for the given URI. If width and height are not 0, the frames are
+// scaled to this size (with borders to keep the aspect ratio).
+func New(u fyne.URI, width, height int, timeout time.Duration) (*Grabber, error) {
+	source, err := utils.SourceElement(u)
+	if err != nil {
+		return nil, err
+	}
+
+	caps := "video/x-raw,format=" + streamer.RawVideoFormat
+	if width > 0 && height > 0 {
+		caps += fmt.Sprintf(",width=%d,height=%d,pixel-aspect-ratio=1/1", width, height)
+	}
+
+	pipeline, err := utils.NewPipelineFromString(fmt.Sprintf(`
+    %[1]s !
+    decodebin !
+    videoconvert !
+    videoscale add-borders=true !
+    %[2]s !
+    appsink name=%[3]s sync=false max-buffers=1
+    `, source, caps, streamer.AppSinkElementName))
+	if err != nil {
+		return nil, err
+	}
+
+	element, err := pipeline.GetElementByName(streamer.AppSinkElementName)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	g := &Grabber{
+		pipeline: pipeline,
+		appSink:  app.SinkFromElement(element),
+		timeout:  timeout,
+	}
+
+	if err := pipeline.SetState(gst.StatePaused); err != nil {
+		g.Close()
+		return nil, err
+	}
+	if ret, _ := pipeline.GetState(gst.StatePaused, gst.ClockTime(timeout)); ret == gst.StateChangeFailure {
+		g.Close()
+		return nil, fmt.Errorf("failed to preroll %s", u)
+	}
+	return g, nil
+}
+
+// Close stops the pipeline. The Grabber cannot be used after that.
+func (g *Grabber) Close() {
+	if g.pipeline == nil {
+		return
+	}
+	g.pipeline.SetState(gst.StateNull)
+	g.pipeline = nil
+}
+
+// Duration returns the duration of the media.
+func (g *Grabber) Duration() (time.Duration, error) {
+	if g.pipeline == nil {
+		return 0, streamer.ErrNoPipeline
+	}
+	ok, duration := g.pipeline.QueryDuration(gst.FormatTime)
+	if !ok {
+		return 0, streamer.ErrNoDuration
+	}
+	return time.Duration(duration), nil
+}
+
+// FrameAt returns a copy of the frame at the given position.
+func (g *Grabber) FrameAt(pos time.Duration) (image.Image, error) {
+	if g.pipeline == nil {
+		return nil, streamer.ErrNoPipeline
+	}
+	if !g.pipeline.SeekTime(pos, gst.SeekFlagFlush|gst.SeekFlagAccurate) {
+		return nil, streamer.ErrSeekFailed
+	}
+
+	sample := g.appSink.TryPullPreroll(gst.ClockTime(g.timeout))
+	if sample == nil {
+		return nil, fmt.Errorf("%w at %v", streamer.ErrNoFrame, pos)
+	}
+	return sampleToImage(sample)
+}
+
+// sampleToImage copies a raw RGBA sample to a new image.
+func sampleToImage(sample *gst.Sample) (image.Image, error) {
+	caps := sample.GetCaps()
+	if caps == nil || caps.GetSize() == 0 {
+		return nil, fmt.Errorf("the sample has no caps")
+	}
+	structure := caps.GetStructureAt(0)
+	format, _ := structure.GetValue("format")
+	if format != streamer.RawVideoFormat {
+		return nil, fmt.Errorf("%w: %v", streamer.ErrUnsupportedRawFormat, format)
+	}
+	width, _ := structure.GetValue("width")
+	height, _ := structure.GetValue("height")
+	w, _ := width.(int)
+	h, _ := height.(int)
+
+	buffer := sample.GetBuffer()
+	if buffer == nil {
+		return nil, fmt.Errorf("the sample has no buffer")
+	}
+	defer buffer.Unmap()
+	mapInfo := buffer.Map(gst.MapRead)
+	if mapInfo == nil {
+		return nil, fmt.Errorf("failed to map the buffer")
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	if err := utils.CopyRawFrame(img, utils.MappedBytes(mapInfo)); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
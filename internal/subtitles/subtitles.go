@@ -0,0 +1,188 @@
+// Package subtitles parses the SRT and WebVTT subtitle files.
+package subtitles
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// timingSeparator separates the start and end times of a cue, e.g. "00:00:01,000 --> 00:00:02,500".
+const timingSeparator = "-->"
+
+var (
+	// tags are the html like tags of SRT and WebVTT, e.g. <i>, <c.yellow>, <v Bob>...
+	tags = regexp.MustCompile(`<[^>]*>`)
+
+	// assTags are the position tags that some SRT files have, e.g. {\an8}.
+	assTags = regexp.MustCompile(`\{\\[^}]*\}`)
+)
+
+// Cue is a text to display between two times.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string // the raw text, with the tags, lines are separated by "\n"
+}
+
+// Line is a line of text of a cue, without the tags.
+type Line struct {
+	Text   string
+	Italic bool
+	Bold   bool
+}
+
+// Lines returns the lines of the cue without the tags. The style of the line
+// is italic or bold if the line has an italic or a bold tag.
+func (c Cue) Lines() []Line {
+	var lines []Line
+	for _, text := range strings.Split(c.Text, "\n") {
+		lower := strings.ToLower(text)
+		line := Line{
+			Italic: strings.Contains(lower, "<i>"),
+			Bold:   strings.Contains(lower, "<b>"),
+		}
+		text = tags.ReplaceAllString(text, "")
+		text = assTags.ReplaceAllString(text, "")
+		line.Text = strings.TrimSpace(html.UnescapeString(text))
+		if line.Text != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Active returns the cues to display at the given position.
+func Active(cues []Cue, pos time.Duration) []Cue {
+	var active []Cue
+	for _, cue := range cues {
+		if cue.Start > pos {
+			// the cues are sorted by start time
+			break
+		}
+		if pos < cue.End {
+			active = append(active, cue)
+		}
+	}
+	return active
+}
+
+// Parse reads the cues of a SRT or a WebVTT file. The cues are sorted by start time.
+//
+// Both formats are made of blocks separated by empty lines. The blocks of cues have a
+// timing line, the other blocks (WebVTT header, notes, styles...) are ignored.
+func Parse(r io.Reader) ([]Cue, error) {
+	var (
+		cues    []Cue
+		cue     *Cue
+		lineNum int
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff") // the BOM
+		}
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			// end of block
+			if cue != nil {
+				cues = append(cues, *cue)
+				cue = nil
+			}
+		case cue == nil && strings.Contains(line, timingSeparator):
+			start, end, err := parseTiming(line)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", streamer.ErrInvalidSubtitles, lineNum, err)
+			}
+			cue = &Cue{Start: start, End: end}
+		case cue != nil:
+			if cue.Text != "" {
+				cue.Text += "\n"
+			}
+			cue.Text += line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if cue != nil {
+		cues = append(cues, *cue)
+	}
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("%w: no cue found", streamer.ErrInvalidSubtitles)
+	}
+
+	sort.SliceStable(cues, func(i, j int) bool {
+		return cues[i].Start < cues[j].Start
+	})
+	return cues, nil
+}
+
+// parseTiming parses a timing line. The WebVTT settings after the end time are ignored.
+func parseTiming(line string) (time.Duration, time.Duration, error) {
+	parts := strings.SplitN(line, timingSeparator, 2)
+	start, err := parseTimestamp(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(parts[1])
+	if len(fields) == 0 {
+		return 0, 0, fmt.Errorf("no end time")
+	}
+	end, err := parseTimestamp(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// parseTimestamp parses "hh:mm:ss,mmm" (SRT) or "hh:mm:ss.mmm" and "mm:ss.mmm" (WebVTT).
+func parseTimestamp(s string) (time.Duration, error) {
+	s = strings.Replace(strings.TrimSpace(s), ",", ".", 1)
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	// the seconds and the milliseconds
+	secs := strings.SplitN(parts[len(parts)-1], ".", 2)
+	seconds, err := strconv.Atoi(secs[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	d := time.Duration(seconds) * time.Second
+	if len(secs) == 2 {
+		// some files have less than 3 digits, e.g. "00:00:01,5"
+		fraction := secs[1]
+		if len(fraction) == 0 || len(fraction) > 3 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		millis, err := strconv.Atoi(fraction + strings.Repeat("0", 3-len(fraction)))
+		if err != nil {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		d += time.Duration(millis) * time.Millisecond
+	}
+
+	// the minutes, and the hours if any
+	units := []time.Duration{time.Minute, time.Hour}
+	for i, unit := range units[:len(parts)-1] {
+		n, err := strconv.Atoi(parts[len(parts)-2-i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		d += time.Duration(n) * unit
+	}
+	return d, nil
+}
@@ -0,0 +1,81 @@
+package subtitles
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSRT = "\ufeff1\r\n" +
+	"00:00:01,000 --> 00:00:02,500\r\n" +
+	"Hello\r\n" +
+	"\r\n" +
+	"2\r\n" +
+	"00:00:03,000 --> 00:00:04,000\r\n" +
+	"{\\an8}<i>Two</i>\r\n" +
+	"<b>lines</b> &amp; more\r\n"
+
+const testWebVTT = `WEBVTT - some title
+
+NOTE a comment
+
+STYLE
+::cue { color: yellow }
+
+intro
+00:01.000 --> 00:02.500 align:start position:10%
+<v Bob>Hello</v>
+
+01:00:03.000 --> 01:00:04.000
+<c.yellow>Later</c>
+`
+
+func TestParseSRT(t *testing.T) {
+	cues, err := Parse(strings.NewReader(testSRT))
+	assert.Nil(t, err)
+	assert.Len(t, cues, 2)
+
+	assert.Equal(t, time.Second, cues[0].Start)
+	assert.Equal(t, 2500*time.Millisecond, cues[0].End)
+	assert.Equal(t, []Line{{Text: "Hello"}}, cues[0].Lines())
+
+	assert.Equal(t, []Line{
+		{Text: "Two", Italic: true},
+		{Text: "lines & more", Bold: true},
+	}, cues[1].Lines())
+}
+
+func TestParseWebVTT(t *testing.T) {
+	cues, err := Parse(strings.NewReader(testWebVTT))
+	assert.Nil(t, err)
+	assert.Len(t, cues, 2)
+
+	assert.Equal(t, time.Second, cues[0].Start)
+	assert.Equal(t, 2500*time.Millisecond, cues[0].End)
+	assert.Equal(t, "Hello", cues[0].Lines()[0].Text)
+
+	assert.Equal(t, time.Hour+3*time.Second, cues[1].Start)
+	assert.Equal(t, "Later", cues[1].Lines()[0].Text)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse(strings.NewReader("WEBVTT\n\n"))
+	assert.True(t, errors.Is(err, streamer.ErrInvalidSubtitles))
+
+	_, err = Parse(strings.NewReader("1\n00:00:AA,000 --> 00:00:02,000\nHello\n"))
+	assert.True(t, errors.Is(err, streamer.ErrInvalidSubtitles))
+}
+
+func TestActive(t *testing.T) {
+	cues, err := Parse(strings.NewReader(testSRT))
+	assert.Nil(t, err)
+
+	assert.Empty(t, Active(cues, 500*time.Millisecond))
+	assert.Len(t, Active(cues, 1500*time.Millisecond), 1)
+	assert.Empty(t, Active(cues, 2500*time.Millisecond))
+	assert.Equal(t, cues[1], Active(cues, 3*time.Second)[0])
+}
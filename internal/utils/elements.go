@@ -0,0 +1,20 @@
+package utils
+
+// #cgo pkg-config: gstreamer-1.0
+// #include <gst/gst.h>
+import "C"
+
+import (
+	"github.com/go-gst/go-gst/gst"
+)
+
+// SetLockedState makes the element ignore the state changes of its parent, so that it keeps its
+// own state, e.g. a branch that is finalized while the pipeline plays. go-gst doesn't wrap
+// gst_element_set_locked_state.
+func SetLockedState(element *gst.Element, locked bool) {
+	var value C.gboolean
+	if locked {
+		value = C.TRUE
+	}
+	C.gst_element_set_locked_state((*C.GstElement)(element.Unsafe()), value)
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"fmt"
+	"image"
+	"unsafe"
+
+	"github.com/go-gst/go-gst/gst"
+)
+
+// MappedBytes returns the mapped memory as a byte slice, without copying it.
+// The slice must not be used after the buffer is unmapped.
+func MappedBytes(mapInfo *gst.MapInfo) []byte {
+	size := int(mapInfo.Size())
+	if size == 0 || mapInfo.Data() == nil {
+		return nil
+	}
+	return unsafe.Slice((*byte)(mapInfo.Data()), size)
+}
+
+// CopyRawFrame copies a raw RGBA frame to the given image, that must have the size of the frame.
+// The stride of the data can be padded by the producer, so it is computed from the data size.
+func CopyRawFrame(dst *image.NRGBA, data []byte) error {
+	width, height := dst.Rect.Dx(), dst.Rect.Dy()
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid frame size %dx%d", width, height)
+	}
+	stride := len(data) / height
+	if stride < width*4 {
+		return fmt.Errorf("buffer too small for a %dx%d frame: %d bytes", width, height, len(data))
+	}
+	if stride == dst.Stride {
+		copy(dst.Pix, data)
+		return nil
+	}
+	for y := 0; y < height; y++ {
+		copy(dst.Pix[y*dst.Stride:(y+1)*dst.Stride], data[y*stride:])
+	}
+	return nil
+}
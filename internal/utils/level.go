@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// levelMessageName is the name of the structure of the messages posted by the level element.
+const levelMessageName = "level"
+
+// serializedListField finds the list fields in a serialized structure,
+// e.g. `rms=(GValueArray)< -20.5, -21.3 >`.
+var serializedListField = regexp.MustCompile(`([\w-]+)=\([^)]*\)\s*[<{]([^>}]*)[>}]`)
+
+// PostAudioMessages makes the spectrum and the level elements of the pipeline post their messages
+// on the bus, or stop to post them when nothing listens to them. The missing elements are ignored.
+func PostAudioMessages(p *gst.Pipeline, spectrum, level bool) {
+	if p == nil {
+		return
+	}
+	if element, err := p.GetElementByName(streamer.SpectrumElementName); err == nil && element != nil {
+		element.SetProperty("post-messages", spectrum)
+	}
+	if element, err := p.GetElementByName(streamer.LevelElementName); err == nil && element != nil {
+		element.SetProperty("post-messages", level)
+	}
+}
+
+// AudioLevels returns the RMS and peak levels (in dB) of each channel found in a message posted
+// by a level element. It returns nil slices if the structure is not a level message.
+func AudioLevels(s *gst.Structure) (rms, peak []float64) {
+	if s == nil || s.Name() != levelMessageName {
+		return nil, nil
+	}
+	return structureFloats(s, "rms"), structureFloats(s, "peak")
+}
+
+// structureFloats returns the numbers of a list field of the structure. The level element posts
+// GValueArray fields that go-gst cannot convert, so they are read from the serialized structure.
+func structureFloats(s *gst.Structure, field string) []float64 {
+	if value, err := s.GetValue(field); err == nil {
+		if values := FloatList(value); values != nil {
+			return values
+		}
+	}
+
+	for _, match := range serializedListField.FindAllStringSubmatch(s.String(), -1) {
+		if match[1] != field {
+			continue
+		}
+		var values []float64
+		for _, item := range strings.Split(match[2], ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			// the items may be typed, e.g. "(double)-20.5"
+			if i := strings.Index(item, ")"); strings.HasPrefix(item, "(") && i > 0 {
+				item = item[i+1:]
+			}
+			value, err := strconv.ParseFloat(item, 64)
+			if err != nil {
+				return nil
+			}
+			values = append(values, value)
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"github.com/go-gst/go-glib/glib"
+	"github.com/go-gst/go-gst/gst"
+)
+
+// PadMediaType returns the name of the caps of the pad, e.g. "audio/x-raw". If the caps are not
+// negotiated yet, the pad is queried.
+func PadMediaType(pad *gst.Pad) string {
+	caps := pad.GetCurrentCaps()
+	if caps == nil {
+		caps = pad.QueryCaps(nil)
+	}
+	if caps == nil || caps.GetSize() == 0 {
+		return ""
+	}
+	return caps.GetStructureAt(0).Name()
+}
+
+// PadCapsString returns a string field of the caps of the pad, e.g. the "media" of a RTP stream.
+// If the caps are not negotiated yet, the pad is queried.
+func PadCapsString(pad *gst.Pad, field string) (string, bool) {
+	caps := pad.GetCurrentCaps()
+	if caps == nil {
+		caps = pad.QueryCaps(nil)
+	}
+	if caps == nil || caps.GetSize() == 0 {
+		return "", false
+	}
+	value, err := caps.GetStructureAt(0).GetValue(field)
+	if err != nil {
+		return "", false
+	}
+	s, ok := value.(string)
+	return s, ok
+}
+
+// PadCapsInt returns an integer field of the current caps of the pad, e.g. "channels".
+func PadCapsInt(pad *gst.Pad, field string) (int, bool) {
+	caps := pad.GetCurrentCaps()
+	if caps == nil || caps.GetSize() == 0 {
+		return 0, false
+	}
+	value, err := caps.GetStructureAt(0).GetValue(field)
+	if err != nil {
+		return 0, false
+	}
+	i, ok := value.(int)
+	return i, ok
+}
+
+// PadTagString returns the tag of the stream that goes through the pad. The tags are read
+// from the sticky tag events of the pad.
+func PadTagString(pad *gst.Pad, tag gst.Tag) (string, bool) {
+	for i := uint(0); ; i++ {
+		event := pad.GetStickyEvent(gst.EventTypeTag, i)
+		if event == nil {
+			return "", false
+		}
+		if value, ok := event.ParseTag().GetString(tag); ok {
+			return value, true
+		}
+	}
+}
+
+// SetPadProperty sets a property of the element that is a pad, e.g. the "active-pad" of
+// an input-selector. The value is created with the exact type of the property, as glib requires.
+func SetPadProperty(element *gst.Element, name string, pad *gst.Pad) error {
+	propType, err := element.GetPropertyType(name)
+	if err != nil {
+		return err
+	}
+	value, err := glib.ValueInit(propType)
+	if err != nil {
+		return err
+	}
+	value.SetInstance(uintptr(pad.Unsafe()))
+	return element.SetPropertyValue(name, value)
+}
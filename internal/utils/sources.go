@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// SourceElement returns the GStreamer source element description to read the given URI,
+// e.g. a "filesrc" for local files or a "souphttpsrc" for http(s) locations.
+func SourceElement(u fyne.URI) (string, error) {
+	switch u.Scheme() {
+	case "http", "https":
+		return fmt.Sprintf("souphttpsrc location=%q", u.String()), nil
+	case "file":
+		return fmt.Sprintf("filesrc location=%q", u.Path()), nil
+	default:
+		return "", fmt.Errorf("%w: %q", streamer.ErrUnsupportedScheme, u.Scheme())
+	}
+}
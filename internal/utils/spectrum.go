@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// spectrumMessageName is the name of the structure of the messages posted by the spectrum element.
+const spectrumMessageName = "spectrum"
+
+// ConfigureSpectrum sets the number of bands and the interval of the spectrum element of the pipeline.
+// Zero values are ignored, so that the element keeps its current setting.
+func ConfigureSpectrum(p *gst.Pipeline, bands int, interval time.Duration) error {
+	if p == nil {
+		return streamer.ErrNoPipeline
+	}
+	spectrum, err := p.GetElementByName(streamer.SpectrumElementName)
+	if err != nil || spectrum == nil {
+		return streamer.ErrNoSpectrum
+	}
+	if bands > 0 {
+		// the property is a guint, the type must match
+		if err := spectrum.SetProperty("bands", uint(bands)); err != nil {
+			return fmt.Errorf("failed to set bands property: %w", err)
+		}
+	}
+	if interval > 0 {
+		// the property is a guint64 in nanoseconds
+		if err := spectrum.SetProperty("interval", uint64(interval.Nanoseconds())); err != nil {
+			return fmt.Errorf("failed to set interval property: %w", err)
+		}
+	}
+	return nil
+}
+
+// SpectrumMagnitudes returns the magnitudes (in dB) of the bands found in a message posted
+// by a spectrum element, from the lowest to the highest frequency. It returns nil if the
+// structure is not a spectrum message.
+func SpectrumMagnitudes(s *gst.Structure) []float64 {
+	if s == nil || s.Name() != spectrumMessageName {
+		return nil
+	}
+	value, err := s.GetValue("magnitude")
+	if err != nil {
+		return nil
+	}
+	return FloatList(value)
+}
+
+// FloatList converts a GstValueList or a GstValueArray of numbers to a slice of float64.
+// Values that are not numbers are set to 0.
+func FloatList(value interface{}) []float64 {
+	var (
+		size    uint
+		valueAt func(uint) interface{}
+	)
+	switch list := value.(type) {
+	case *gst.ValueListValue:
+		size, valueAt = list.Size(), list.ValueAt
+	case *gst.ValueArrayValue:
+		size, valueAt = list.Size(), list.ValueAt
+	default:
+		return nil
+	}
+
+	values := make([]float64, size)
+	for i := range values {
+		switch v := valueAt(uint(i)).(type) {
+		case float32:
+			values[i] = float64(v)
+		case float64:
+			values[i] = v
+		}
+	}
+	return values
+}
@@ -0,0 +1,65 @@
+package utils
+
+// #cgo pkg-config: gstreamer-1.0
+// #include <gst/gst.h>
+import "C"
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/go-gst/go-gst/gst"
+)
+
+// TOCChapter is a chapter of a table of contents. End is 0 if the container doesn't give it.
+type TOCChapter struct {
+	Title string
+	Start time.Duration
+	End   time.Duration
+}
+
+// TOCChapters returns the chapters of the table of contents, in the order of the container.
+// Only the first edition is read when the container has several ones (e.g. Matroska), and the
+// sub-chapters are ignored.
+//
+// The entries are listed here because the go-gst TOC.GetEntries method doesn't iterate the list.
+func TOCChapters(toc *gst.TOC) []TOCChapter {
+	if toc == nil {
+		return nil
+	}
+	return tocChapters(C.gst_toc_get_entries((*C.GstToc)(unsafe.Pointer(toc.Instance()))))
+}
+
+// tocChapters reads the chapters of a list of entries, the alternatives are replaced by the
+// entries of the first one.
+func tocChapters(list *C.GList) []TOCChapter {
+	var chapters []TOCChapter
+	for ; list != nil; list = list.next {
+		entry := (*C.GstTocEntry)(list.data)
+		if C.gst_toc_entry_is_alternative(entry) != 0 {
+			return tocChapters(C.gst_toc_entry_get_sub_entries(entry))
+		}
+		if C.gst_toc_entry_get_entry_type(entry) != C.GST_TOC_ENTRY_TYPE_CHAPTER {
+			continue
+		}
+		chapters = append(chapters, tocChapter(gst.FromGstTocEntryUnsafeNone(unsafe.Pointer(entry))))
+	}
+	return chapters
+}
+
+// tocChapter returns the title and the times of the entry.
+func tocChapter(entry *gst.TOCEntry) TOCChapter {
+	var chapter TOCChapter
+	if tags := entry.GetTags(); tags != nil {
+		chapter.Title, _ = tags.GetString(gst.TagTitle)
+	}
+	if ok, start, stop := entry.GetStartStopTimes(); ok {
+		if start > 0 {
+			chapter.Start = time.Duration(start)
+		}
+		if stop > 0 {
+			chapter.End = time.Duration(stop)
+		}
+	}
+	return chapter
+}
@@ -22,3 +22,13 @@ type MediaSeeker interface {
 type MediaDuration interface {
 	Duration() (time.Duration, error)
 }
+
+type MediaSpectrum interface {
+	SetOnSpectrum(func([]float64))
+	SetSpectrumBands(int) error
+	SetSpectrumInterval(time.Duration) error
+}
+
+type MediaAudioLevel interface {
+	SetOnAudioLevel(func(rms, peak []float64))
+}
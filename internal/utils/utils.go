@@ -1,11 +1,14 @@
 package utils
 
 import (
+	"bytes"
 	"regexp"
+	"text/template"
 
 	"fyne.io/fyne/v2"
 	"github.com/go-gst/go-glib/glib"
 	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
 )
 
 var (
@@ -64,6 +67,20 @@ func NewPipelineFromString(pipeline string) (*gst.Pipeline, error) {
 	return gst.NewPipelineFromString(RemoveComments(pipeline))
 }
 
+// NewPipelineFromTemplate creates a new pipeline from the given template (text/template).
+// The template data is the streamer.ElementMap, so the element names can be used in the template.
+func NewPipelineFromTemplate(pipeline string) (*gst.Pipeline, error) {
+	tpl, err := template.New("pipeline").Parse(pipeline)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	if err := tpl.Execute(buf, streamer.ElementMap); err != nil {
+		return nil, err
+	}
+	return NewPipelineFromString(buf.String())
+}
+
 // AddToPipeline is a convenience function to add all elements to the pipeline from a map built with the mustCreateElement function.
 func AddToPipeline(pipeline *gst.Pipeline, elements map[string]*gst.Element) error {
 	err := pipeline.AddMany(getElementsFromMap(elements)...)
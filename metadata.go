@@ -0,0 +1,108 @@
+package streamer
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"  // cover images
+	_ "image/jpeg" // cover images
+	_ "image/png"  // cover images
+	"time"
+
+	"github.com/go-gst/go-gst/gst"
+)
+
+// Metadata is the information of a media found in its tags, e.g. the title of a movie or the
+// artist and the album of a song. The fields are empty if the media doesn't have the tag.
+type Metadata struct {
+	Title   string
+	Artist  string
+	Album   string
+	Date    time.Time // the release date, only the year may be meaningful
+	Genre   string
+	Comment string
+
+	Container     string // container format, e.g. "Matroska"
+	VideoCodec    string // e.g. "H.264 (High Profile)"
+	AudioCodec    string // e.g. "MPEG-4 AAC"
+	SubtitleCodec string
+	VideoBitrate  uint   // in bits per second
+	AudioBitrate  uint   // in bits per second
+	Language      string // ISO 639 language code, e.g. "en"
+
+	Cover image.Image // the embedded cover image (e.g. album art), nil if there is none
+}
+
+// AddTags merges the tags into the metadata. The tags that are present replace the previous
+// values, the others are kept, so the tags of all the streams of a media can be accumulated.
+func (m *Metadata) AddTags(tags *gst.TagList) {
+	if tags == nil {
+		return
+	}
+	texts := []struct {
+		tag   gst.Tag
+		field *string
+	}{
+		{gst.TagTitle, &m.Title},
+		{gst.TagArtist, &m.Artist},
+		{gst.TagAlbum, &m.Album},
+		{gst.TagGenre, &m.Genre},
+		{gst.TagComment, &m.Comment},
+		{gst.TagContainerFormat, &m.Container},
+		{gst.TagVideoCodec, &m.VideoCodec},
+		{gst.TagAudioCodec, &m.AudioCodec},
+		{gst.TagSubtitleCodec, &m.SubtitleCodec},
+		{gst.TagLanguageCode, &m.Language},
+	}
+	for _, text := range texts {
+		if value, ok := tags.GetString(text.tag); ok && value != "" {
+			*text.field = value
+		}
+	}
+
+	if date, ok := tags.GetDateTime(gst.TagDateTime); ok {
+		m.Date = date
+	} else if date, ok := tags.GetDate(gst.TagDate); ok {
+		m.Date = date
+	}
+
+	// the bitrate tags are sent with the codec of the stream, the stream is unknown if the
+	// list has both codecs (e.g. the merged tags of a container)
+	bitrate, ok := tags.GetUint32(gst.TagBitrate)
+	if !ok {
+		bitrate, ok = tags.GetUint32(gst.TagNominalBitrate)
+	}
+	if ok {
+		_, video := tags.GetString(gst.TagVideoCodec)
+		_, audio := tags.GetString(gst.TagAudioCodec)
+		switch {
+		case video && !audio:
+			m.VideoBitrate = uint(bitrate)
+		case audio && !video:
+			m.AudioBitrate = uint(bitrate)
+		}
+	}
+
+	for _, tag := range []gst.Tag{gst.TagImage, gst.TagPreviewImage} {
+		if cover := tagImage(tags, tag); cover != nil {
+			m.Cover = cover
+			break
+		}
+	}
+}
+
+// tagImage decodes the image of the tag, nil if there is none or if the format is not supported.
+func tagImage(tags *gst.TagList, tag gst.Tag) image.Image {
+	sample, ok := tags.GetSample(tag)
+	if !ok || sample == nil {
+		return nil
+	}
+	buffer := sample.GetBuffer()
+	if buffer == nil {
+		return nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		return nil
+	}
+	return img
+}
@@ -0,0 +1,27 @@
+package streamer
+
+import (
+	"testing"
+
+	"github.com/go-gst/go-gst/gst"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataBitrate(t *testing.T) {
+	gst.Init(nil)
+
+	var metadata Metadata
+	metadata.AddTags(gst.NewTagListFromString(`taglist, video-codec=(string)Theora, bitrate=(uint)500000`))
+	metadata.AddTags(gst.NewTagListFromString(`taglist, audio-codec=(string)Vorbis, nominal-bitrate=(uint)128000`))
+	assert.Equal(t, "Theora", metadata.VideoCodec)
+	assert.Equal(t, "Vorbis", metadata.AudioCodec)
+	assert.Equal(t, uint(500000), metadata.VideoBitrate)
+	assert.Equal(t, uint(128000), metadata.AudioBitrate)
+
+	// the stream of the bitrate is unknown, the previous values are kept
+	metadata.AddTags(gst.NewTagListFromString(`taglist, video-codec=(string)VP8, audio-codec=(string)Opus, bitrate=(uint)42`))
+	assert.Equal(t, "VP8", metadata.VideoCodec)
+	assert.Equal(t, "Opus", metadata.AudioCodec)
+	assert.Equal(t, uint(500000), metadata.VideoBitrate)
+	assert.Equal(t, uint(128000), metadata.AudioBitrate)
+}
@@ -0,0 +1,24 @@
+/*
+Package playlist proposes a list of media to play one after the other in a video or audio player.
+
+The playlist opens the selected entry in the attached player, and the player plays the next entry
+when the current one ends. The entries can be played in order or shuffled, and repeated.
+
+	list := playlist.New(uri1, uri2, uri3)
+	list.SetRepeat(playlist.RepeatAll)
+	list.SetOnCurrentChanged(func(index int, entry playlist.Entry) {
+		window.SetTitle(entry.Name())
+	})
+
+	player := video.NewPlayer()
+	player.SetPlaylist(list)
+	list.SetCurrent(0) // opens and plays the first entry
+
+Any type with the Open and Play methods can be attached with Attach.
+
+The M3U (and extended M3U), PLS and XSPF playlist files are read with Load or Parse, and written
+with Write. The players also load them when a playlist file is given to their Open method.
+
+	list, err := playlist.Load(storage.NewFileURI("/home/me/Music/favorites.m3u"))
+*/
+package playlist
@@ -0,0 +1,245 @@
+package playlist
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// sniffSize is the number of bytes read at the beginning of a file to detect its format.
+const sniffSize = 512
+
+// Format is a playlist file format.
+type Format int
+
+const (
+	// FormatUnknown is not a playlist file.
+	FormatUnknown Format = iota
+
+	// FormatM3U is the M3U format, with the extended "#EXTINF" lines for the titles and durations.
+	// The M3U8 files are the same in UTF-8, the HLS manifests that have the same extension are
+	// not playlist files.
+	FormatM3U
+
+	// FormatPLS is the PLS format, an ini file with a "[playlist]" section.
+	FormatPLS
+
+	// FormatXSPF is the XML Shareable Playlist Format.
+	FormatXSPF
+)
+
+// formats are the extensions and the MIME types of the playlist formats.
+var formats = []struct {
+	format     Format
+	extensions []string
+	mimeTypes  []string
+}{
+	{FormatM3U, []string{".m3u", ".m3u8"}, []string{"audio/x-mpegurl", "audio/mpegurl"}},
+	{FormatPLS, []string{".pls"}, []string{"audio/x-scpls"}},
+	{FormatXSPF, []string{".xspf"}, []string{"application/xspf+xml"}},
+}
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatM3U:
+		return "M3U"
+	case FormatPLS:
+		return "PLS"
+	case FormatXSPF:
+		return "XSPF"
+	default:
+		return "unknown"
+	}
+}
+
+// DetectFormat returns the playlist format of the file at the given location, from its extension
+// or its MIME type, or from its content for the local files. It returns FormatUnknown for media
+// files and for HLS manifests.
+func DetectFormat(u fyne.URI) Format {
+	format := formatFromName(u)
+	if format == FormatUnknown && u.Scheme() != "file" {
+		// don't start to download a media to sniff it
+		return FormatUnknown
+	}
+
+	reader, err := storage.Reader(u)
+	if err != nil {
+		if strings.ToLower(u.Extension()) == ".m3u8" && u.Scheme() != "file" {
+			// the remote M3U8 files are most often HLS manifests
+			return FormatUnknown
+		}
+		return format
+	}
+	defer reader.Close()
+	head := make([]byte, sniffSize)
+	n, _ := io.ReadFull(reader, head)
+	if n == 0 {
+		return format
+	}
+	if sniffed := sniffFormat(head[:n]); sniffed != FormatUnknown || isHLS(head[:n]) {
+		return sniffed
+	}
+	return format
+}
+
+// Load reads the playlist file at the given location. The relative locations of the entries are
+// resolved from the location of the file.
+func Load(u fyne.URI) (*Playlist, error) {
+	format := DetectFormat(u)
+	if format == FormatUnknown {
+		return nil, fmt.Errorf("%w: %s", streamer.ErrUnknownPlaylistFormat, u.Name())
+	}
+	reader, err := storage.Reader(u)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	entries, err := Parse(reader, format, u)
+	if err != nil {
+		return nil, err
+	}
+	p := New()
+	p.AddEntries(entries...)
+	return p, nil
+}
+
+// Parse reads the entries of a playlist file. The relative locations are resolved from base,
+// the location of the file, that can be nil if there is none.
+func Parse(r io.Reader, format Format, base fyne.URI) ([]Entry, error) {
+	var entries []Entry
+	var err error
+	switch format {
+	case FormatM3U:
+		entries, err = parseM3U(r, base)
+	case FormatPLS:
+		entries, err = parsePLS(r, base)
+	case FormatXSPF:
+		entries, err = parseXSPF(r, base)
+	default:
+		return nil, fmt.Errorf("%w: %v", streamer.ErrUnknownPlaylistFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: no entry", streamer.ErrInvalidPlaylist)
+	}
+	return entries, nil
+}
+
+// Write writes the entries in a playlist file of the given format.
+func Write(w io.Writer, format Format, entries []Entry) error {
+	switch format {
+	case FormatM3U:
+		return writeM3U(w, entries)
+	case FormatPLS:
+		return writePLS(w, entries)
+	case FormatXSPF:
+		return writeXSPF(w, entries)
+	default:
+		return fmt.Errorf("%w: %v", streamer.ErrUnknownPlaylistFormat, format)
+	}
+}
+
+// formatFromName returns the format given by the extension or the MIME type of the location.
+func formatFromName(u fyne.URI) Format {
+	extension := strings.ToLower(u.Extension())
+	mimeType := strings.ToLower(u.MimeType())
+	for _, f := range formats {
+		for _, e := range f.extensions {
+			if extension == e {
+				return f.format
+			}
+		}
+		for _, m := range f.mimeTypes {
+			if mimeType == m {
+				return f.format
+			}
+		}
+	}
+	return FormatUnknown
+}
+
+// sniffFormat returns the format found at the beginning of a file.
+func sniffFormat(head []byte) Format {
+	head = bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")))
+	switch {
+	case isHLS(head):
+		return FormatUnknown
+	case bytes.HasPrefix(head, []byte("#EXTM3U")):
+		return FormatM3U
+	case bytes.HasPrefix(bytes.ToLower(head), []byte("[playlist]")):
+		return FormatPLS
+	case bytes.Contains(head, []byte("<playlist")) && bytes.Contains(head, []byte("xspf.org/ns")):
+		return FormatXSPF
+	default:
+		return FormatUnknown
+	}
+}
+
+// isHLS returns true if the M3U file is a HLS manifest, that has "#EXT-X-" tags.
+func isHLS(head []byte) bool {
+	return bytes.Contains(head, []byte("#EXT-X-"))
+}
+
+// resolveLocation returns the URI of a location of a playlist: an URI, an absolute path, or a
+// path relative to the location of the playlist file.
+func resolveLocation(location string, base fyne.URI) (fyne.URI, error) {
+	location = strings.TrimSpace(location)
+	if strings.HasPrefix(strings.ToLower(location), "file://") {
+		// the file URIs of the playlists are escaped, e.g. "file:///My%20Music/song.ogg"
+		if path, err := url.PathUnescape(location[len("file://"):]); err == nil {
+			location = path
+		}
+		return storage.NewFileURI(location), nil
+	}
+	if strings.Contains(location, "://") {
+		return storage.ParseURI(location)
+	}
+
+	// paths of the files written on Windows
+	location = strings.ReplaceAll(location, `\`, "/")
+	if filepath.IsAbs(location) || strings.HasPrefix(location, "/") || base == nil {
+		return storage.NewFileURI(location), nil
+	}
+	if base.Scheme() == "file" {
+		return storage.NewFileURI(filepath.Join(filepath.Dir(base.Path()), location)), nil
+	}
+
+	baseURL, err := url.Parse(base.String())
+	if err != nil {
+		return nil, err
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ParseURI(baseURL.ResolveReference(ref).String())
+}
+
+// writtenLocation returns the location of an entry in a playlist file: the path of the local
+// files, that all the players understand, or the URI.
+func writtenLocation(u fyne.URI) string {
+	if u.Scheme() == "file" {
+		return u.Path()
+	}
+	return u.String()
+}
+
+// escapedLocation returns the URI of an entry, with the path of the local files escaped.
+func escapedLocation(u fyne.URI) string {
+	if u.Scheme() == "file" {
+		location := url.URL{Scheme: "file", Path: filepath.ToSlash(u.Path())}
+		return location.String()
+	}
+	return u.String()
+}
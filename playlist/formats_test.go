@@ -0,0 +1,148 @@
+package playlist
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseM3U(t *testing.T) {
+	content := `#EXTM3U
+#EXTINF:123,Artist - First
+first.ogg
+
+# a comment
+#EXTINF:-1 tvg-logo="logo.png",Radio
+http://example.com/stream
+/music/third.ogg
+`
+	base := storage.NewFileURI("/music/list.m3u")
+	entries, err := Parse(strings.NewReader(content), FormatM3U, base)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 3)
+
+	assert.Equal(t, "Artist - First", entries[0].Title)
+	assert.Equal(t, 123*time.Second, entries[0].Duration)
+	assert.Equal(t, "/music/first.ogg", entries[0].URI.Path())
+
+	assert.Equal(t, "Radio", entries[1].Title)
+	assert.Equal(t, time.Duration(0), entries[1].Duration)
+	assert.Equal(t, "http://example.com/stream", entries[1].URI.String())
+
+	assert.Equal(t, "", entries[2].Title)
+	assert.Equal(t, "third.ogg", entries[2].Name())
+
+	_, err = Parse(strings.NewReader("#EXTM3U\n"), FormatM3U, base)
+	assert.True(t, errors.Is(err, streamer.ErrInvalidPlaylist))
+}
+
+func TestParsePLS(t *testing.T) {
+	content := `[playlist]
+File2=http://example.com/second
+Title2=Second
+File1=first.ogg
+Title1=First
+Length1=60
+NumberOfEntries=2
+Version=2
+`
+	base, _ := storage.ParseURI("http://example.com/lists/radio.pls")
+	entries, err := Parse(strings.NewReader(content), FormatPLS, base)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "http://example.com/lists/first.ogg", entries[0].URI.String())
+	assert.Equal(t, time.Minute, entries[0].Duration)
+	assert.Equal(t, "Second", entries[1].Title)
+}
+
+func TestParseXSPF(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <trackList>
+    <track>
+      <location>file:///music/first.ogg</location>
+      <creator>Artist</creator>
+      <title>First</title>
+      <duration>61500</duration>
+    </track>
+    <track>
+      <title>No location</title>
+    </track>
+  </trackList>
+</playlist>
+`
+	entries, err := Parse(strings.NewReader(content), FormatXSPF, nil)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "Artist - First", entries[0].Title)
+	assert.Equal(t, 61500*time.Millisecond, entries[0].Duration)
+	assert.Equal(t, "/music/first.ogg", entries[0].URI.Path())
+}
+
+func TestWriteFormats(t *testing.T) {
+	stream, _ := storage.ParseURI("http://example.com/stream")
+	entries := []Entry{
+		{URI: storage.NewFileURI("/my music/first.ogg"), Title: "First", Duration: 2 * time.Minute},
+		{URI: stream},
+	}
+
+	for _, format := range []Format{FormatM3U, FormatPLS, FormatXSPF} {
+		var buffer bytes.Buffer
+		err := Write(&buffer, format, entries)
+		assert.Nil(t, err, format.String())
+		assert.Equal(t, format, sniffFormat(buffer.Bytes()), format.String())
+
+		parsed, err := Parse(&buffer, format, nil)
+		assert.Nil(t, err, format.String())
+		assert.Len(t, parsed, 2, format.String())
+		assert.Equal(t, entries[0].URI.String(), parsed[0].URI.String(), format.String())
+		assert.Equal(t, entries[0].Title, parsed[0].Title, format.String())
+		assert.Equal(t, entries[0].Duration, parsed[0].Duration, format.String())
+		assert.Equal(t, entries[1].URI.String(), parsed[1].URI.String(), format.String())
+	}
+
+	err := Write(&bytes.Buffer{}, FormatUnknown, entries)
+	assert.True(t, errors.Is(err, streamer.ErrUnknownPlaylistFormat))
+}
+
+func TestDetectFormat(t *testing.T) {
+	test.NewApp() // registers the file repository
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"list.m3u8":   "#EXTM3U\nfirst.ogg\n",
+		"hls.m3u8":    "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:10\nsegment0.ts\n",
+		"radio":       "[playlist]\nFile1=http://example.com/stream\n",
+		"movie.ogv":   "OggS",
+		"tracks.xspf": `<playlist version="1" xmlns="http://xspf.org/ns/0/"></playlist>`,
+	}
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		assert.Nil(t, err)
+	}
+
+	expected := map[string]Format{
+		"list.m3u8":   FormatM3U,
+		"hls.m3u8":    FormatUnknown,
+		"radio":       FormatPLS,
+		"movie.ogv":   FormatUnknown,
+		"tracks.xspf": FormatXSPF,
+	}
+	for name, format := range expected {
+		assert.Equal(t, format, DetectFormat(storage.NewFileURI(filepath.Join(dir, name))), name)
+	}
+
+	list, err := Load(storage.NewFileURI(filepath.Join(dir, "list.m3u8")))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, list.Len())
+	assert.Equal(t, filepath.Join(dir, "first.ogg"), list.Entries()[0].URI.Path())
+}
@@ -0,0 +1,95 @@
+package playlist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// m3uHeader starts the extended M3U files.
+const m3uHeader = "#EXTM3U"
+
+// m3uInfo gives the duration in seconds and the title of the next location, e.g.
+// "#EXTINF:123,Artist - Title". The duration is -1 if it is unknown.
+const m3uInfo = "#EXTINF:"
+
+// parseM3U reads a M3U or an extended M3U file: a location per line, the lines starting with
+// "#" are comments, except the "#EXTINF" lines.
+func parseM3U(r io.Reader, base fyne.URI) ([]Entry, error) {
+	var entries []Entry
+	var info Entry // read in the last "#EXTINF" line
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		switch {
+		case text == "":
+			continue
+		case strings.HasPrefix(text, m3uInfo):
+			info = parseM3UInfo(strings.TrimPrefix(text, m3uInfo))
+			continue
+		case strings.HasPrefix(text, "#"):
+			continue
+		}
+
+		u, err := resolveLocation(text, base)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", streamer.ErrInvalidPlaylist, line, err)
+		}
+		info.URI = u
+		entries = append(entries, info)
+		info = Entry{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// parseM3UInfo reads the duration and the title of an "#EXTINF" line. The attributes that some
+// players add after the duration (e.g. tvg-logo="...") are ignored.
+func parseM3UInfo(info string) Entry {
+	var entry Entry
+	duration := info
+	if i := strings.Index(info, ","); i >= 0 {
+		duration, entry.Title = info[:i], strings.TrimSpace(info[i+1:])
+	}
+	if fields := strings.Fields(duration); len(fields) > 0 {
+		if seconds, err := strconv.ParseFloat(fields[0], 64); err == nil && seconds > 0 {
+			entry.Duration = time.Duration(seconds * float64(time.Second))
+		}
+	}
+	return entry
+}
+
+// writeM3U writes an extended M3U file.
+func writeM3U(w io.Writer, entries []Entry) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintln(writer, m3uHeader)
+	for _, entry := range entries {
+		if entry.URI == nil {
+			continue
+		}
+		if entry.Title != "" || entry.Duration > 0 {
+			fmt.Fprintf(writer, "%s%d,%s\n", m3uInfo, seconds(entry.Duration), entry.Title)
+		}
+		fmt.Fprintln(writer, writtenLocation(entry.URI))
+	}
+	return writer.Flush()
+}
+
+// seconds returns the duration in seconds for the playlist files, -1 if it is unknown.
+func seconds(d time.Duration) int {
+	if d <= 0 {
+		return -1
+	}
+	return int(d.Round(time.Second) / time.Second)
+}
@@ -0,0 +1,421 @@
+package playlist
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// Repeat is the behavior of the playlist at the end of an entry.
+type Repeat int
+
+const (
+	// RepeatNone stops at the end of the last entry.
+	RepeatNone Repeat = iota
+
+	// RepeatOne plays the current entry again.
+	RepeatOne
+
+	// RepeatAll plays the first entry after the last one.
+	RepeatAll
+)
+
+// Player is a widget that can play the entries of a playlist, e.g. a video.Player or an audio.Player.
+type Player interface {
+	Open(fyne.URI) error
+	Play() error
+}
+
+// Entry is an item of a playlist.
+type Entry struct {
+	URI      fyne.URI
+	Title    string        // empty if unknown
+	Duration time.Duration // 0 if unknown
+}
+
+// Name returns the title of the entry, or the name of the location if there is no title.
+func (e Entry) Name() string {
+	if e.Title != "" {
+		return e.Title
+	}
+	if e.URI == nil {
+		return ""
+	}
+	return e.URI.Name()
+}
+
+// Playlist is an ordered list of media to play one after the other. A player is attached with its
+// SetPlaylist method, then the playlist opens the selected entries in the player, and the player
+// advances in the playlist at the end of each entry.
+//
+// The methods are safe for concurrent use.
+type Playlist struct {
+	mu               sync.Mutex
+	entries          []Entry
+	current          int   // index of the current entry, -1 if there is none
+	order            []int // indexes of the entries in the order of play
+	repeat           Repeat
+	shuffle          bool
+	random           *rand.Rand
+	player           Player
+	onCurrentChanged func(index int, entry Entry)
+	onChanged        func()
+}
+
+// New creates a playlist with the given locations.
+func New(uris ...fyne.URI) *Playlist {
+	p := &Playlist{
+		current: -1,
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	p.Add(uris...)
+	return p
+}
+
+// Add appends the locations at the end of the playlist.
+func (p *Playlist) Add(uris ...fyne.URI) {
+	entries := make([]Entry, len(uris))
+	for i, u := range uris {
+		entries[i] = Entry{URI: u}
+	}
+	p.AddEntries(entries...)
+}
+
+// AddEntries appends the entries at the end of the playlist.
+func (p *Playlist) AddEntries(entries ...Entry) {
+	if len(entries) == 0 {
+		return
+	}
+	p.mu.Lock()
+	p.entries = append(p.entries, entries...)
+	p.resetOrder()
+	p.mu.Unlock()
+	p.changed()
+}
+
+// Advance goes to the next entry following the repeat and shuffle modes, and plays it. It is
+// called by the attached player at the end of the stream. It returns false if the playlist is
+// finished, then the player stays at the end of the current entry.
+func (p *Playlist) Advance() bool {
+	p.mu.Lock()
+	index, ok := p.nextIndex(p.repeat == RepeatOne)
+	p.mu.Unlock()
+	if !ok {
+		return false
+	}
+	if err := p.SetCurrent(index); err != nil {
+		fyne.LogError("Failed to play the next entry of the playlist", err)
+		return false
+	}
+	return true
+}
+
+// Attach sets the player that plays the entries. It is called by the SetPlaylist method of the
+// players, use it for other players.
+func (p *Playlist) Attach(player Player) {
+	p.mu.Lock()
+	p.player = player
+	p.mu.Unlock()
+}
+
+// Detach removes the player, if it is the attached one, so that the playlist doesn't play its
+// entries anymore. It is called by the SetPlaylist method of the players when the playlist is
+// replaced or removed.
+func (p *Playlist) Detach(player Player) {
+	p.mu.Lock()
+	if p.player == player {
+		p.player = nil
+	}
+	p.mu.Unlock()
+}
+
+// Clear removes all the entries.
+func (p *Playlist) Clear() {
+	p.mu.Lock()
+	p.entries = nil
+	p.current = -1
+	p.resetOrder()
+	p.mu.Unlock()
+	p.changed()
+}
+
+// Current returns the index of the current entry, or -1 if there is none.
+func (p *Playlist) Current() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current
+}
+
+// Entries returns a copy of the entries, in the order of the list (not the order of play if
+// the playlist is shuffled).
+func (p *Playlist) Entries() []Entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Entry(nil), p.entries...)
+}
+
+// Entry returns the entry at the given index.
+func (p *Playlist) Entry(i int) (Entry, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if i < 0 || i >= len(p.entries) {
+		return Entry{}, p.invalidIndex(i)
+	}
+	return p.entries[i], nil
+}
+
+// Len returns the number of entries.
+func (p *Playlist) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+// Move moves the entry at index from to the index to, the other entries are shifted.
+func (p *Playlist) Move(from, to int) error {
+	p.mu.Lock()
+	if from < 0 || from >= len(p.entries) {
+		defer p.mu.Unlock()
+		return p.invalidIndex(from)
+	}
+	if to < 0 || to >= len(p.entries) {
+		defer p.mu.Unlock()
+		return p.invalidIndex(to)
+	}
+	entry := p.entries[from]
+	p.entries = append(p.entries[:from], p.entries[from+1:]...)
+	p.entries = append(p.entries[:to], append([]Entry{entry}, p.entries[to:]...)...)
+
+	// the current entry follows the moves
+	switch {
+	case p.current == from:
+		p.current = to
+	case from < p.current && p.current <= to:
+		p.current--
+	case to <= p.current && p.current < from:
+		p.current++
+	}
+	p.resetOrder()
+	p.mu.Unlock()
+	p.changed()
+	return nil
+}
+
+// Next plays the next entry. With RepeatAll, the first entry follows the last one. RepeatOne
+// is ignored, so that the user can leave the current entry.
+func (p *Playlist) Next() error {
+	p.mu.Lock()
+	index, ok := p.nextIndex(false)
+	p.mu.Unlock()
+	if !ok {
+		return streamer.ErrEndOfPlaylist
+	}
+	return p.SetCurrent(index)
+}
+
+// Previous plays the previous entry. With RepeatAll, the last entry precedes the first one.
+func (p *Playlist) Previous() error {
+	p.mu.Lock()
+	index, ok := p.previousIndex()
+	p.mu.Unlock()
+	if !ok {
+		return streamer.ErrEndOfPlaylist
+	}
+	return p.SetCurrent(index)
+}
+
+// Remove removes the entry at the given index. If it is the current entry, there is no current
+// entry anymore, the player is not stopped.
+func (p *Playlist) Remove(i int) error {
+	p.mu.Lock()
+	if i < 0 || i >= len(p.entries) {
+		defer p.mu.Unlock()
+		return p.invalidIndex(i)
+	}
+	p.entries = append(p.entries[:i], p.entries[i+1:]...)
+	switch {
+	case p.current == i:
+		p.current = -1
+	case p.current > i:
+		p.current--
+	}
+	p.resetOrder()
+	p.mu.Unlock()
+	p.changed()
+	return nil
+}
+
+// Repeat returns the repeat mode, RepeatNone by default.
+func (p *Playlist) Repeat() Repeat {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.repeat
+}
+
+// Select makes the entry at the given index the current one, without opening it. It is used by
+// the players that open the entry themselves, e.g. the first entry of a loaded playlist file.
+func (p *Playlist) Select(i int) (Entry, error) {
+	p.mu.Lock()
+	if i < 0 || i >= len(p.entries) {
+		defer p.mu.Unlock()
+		return Entry{}, p.invalidIndex(i)
+	}
+	p.current = i
+	entry, onCurrentChanged := p.entries[i], p.onCurrentChanged
+	p.mu.Unlock()
+
+	if onCurrentChanged != nil {
+		onCurrentChanged(i, entry)
+	}
+	return entry, nil
+}
+
+// SetCurrent plays the entry at the given index in the attached player.
+func (p *Playlist) SetCurrent(i int) error {
+	p.mu.Lock()
+	player := p.player
+	p.mu.Unlock()
+	if player == nil {
+		return streamer.ErrNoPlayer
+	}
+
+	entry, err := p.Select(i)
+	if err != nil {
+		return err
+	}
+	if err := player.Open(entry.URI); err != nil {
+		return err
+	}
+	return player.Play()
+}
+
+// SetOnChanged set the function that is called when entries are added, removed or moved.
+func (p *Playlist) SetOnChanged(f func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onChanged = f
+}
+
+// SetOnCurrentChanged set the function that is called when an entry is about to be played, e.g.
+// to select it in a list or to change the title of the window.
+func (p *Playlist) SetOnCurrentChanged(f func(index int, entry Entry)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onCurrentChanged = f
+}
+
+// SetRepeat changes the behavior at the end of the entries.
+func (p *Playlist) SetRepeat(repeat Repeat) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.repeat = repeat
+}
+
+// SetShuffle plays the entries in a random order. Each entry is played once before the order
+// changes, with RepeatAll.
+func (p *Playlist) SetShuffle(shuffle bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.shuffle = shuffle
+	p.resetOrder()
+}
+
+// Shuffle returns true if the entries are played in a random order.
+func (p *Playlist) Shuffle() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.shuffle
+}
+
+// changed calls the onChanged callback.
+func (p *Playlist) changed() {
+	p.mu.Lock()
+	onChanged := p.onChanged
+	p.mu.Unlock()
+	if onChanged != nil {
+		onChanged()
+	}
+}
+
+// invalidIndex returns the error of an index out of the entries. The lock must be held by the caller.
+func (p *Playlist) invalidIndex(i int) error {
+	return fmt.Errorf("%w: %d, there are %d entries", streamer.ErrInvalidEntry, i, len(p.entries))
+}
+
+// nextIndex returns the index of the entry to play after the current one, ok is false if the
+// playlist is finished. The lock must be held by the caller.
+func (p *Playlist) nextIndex(repeatOne bool) (index int, ok bool) {
+	if len(p.entries) == 0 {
+		return 0, false
+	}
+	if p.current < 0 {
+		return p.order[0], true
+	}
+	if repeatOne {
+		return p.current, true
+	}
+	pos := p.position()
+	if pos+1 < len(p.order) {
+		return p.order[pos+1], true
+	}
+	if p.repeat != RepeatAll {
+		return 0, false
+	}
+	if p.shuffle {
+		// a new order for the next round, that doesn't start with the last played entry
+		p.resetOrder()
+		if len(p.order) > 1 {
+			return p.order[1], true
+		}
+	}
+	return p.order[0], true
+}
+
+// position returns the position of the current entry in the order of play, -1 if there is no
+// current entry. The lock must be held by the caller.
+func (p *Playlist) position() int {
+	for pos, index := range p.order {
+		if index == p.current {
+			return pos
+		}
+	}
+	return -1
+}
+
+// previousIndex returns the index of the entry to play before the current one, ok is false if the
+// current entry is the first one. The lock must be held by the caller.
+func (p *Playlist) previousIndex() (index int, ok bool) {
+	if len(p.entries) == 0 || p.current < 0 {
+		return 0, false
+	}
+	pos := p.position()
+	if pos > 0 {
+		return p.order[pos-1], true
+	}
+	if p.repeat != RepeatAll {
+		return 0, false
+	}
+	return p.order[len(p.order)-1], true
+}
+
+// resetOrder computes the order of play. If the playlist is shuffled, the current entry is the
+// first one, so that all the others are played after it. The lock must be held by the caller.
+func (p *Playlist) resetOrder() {
+	p.order = make([]int, len(p.entries))
+	for i := range p.order {
+		p.order[i] = i
+	}
+	if !p.shuffle {
+		return
+	}
+	p.random.Shuffle(len(p.order), func(i, j int) {
+		p.order[i], p.order[j] = p.order[j], p.order[i]
+	})
+	if pos := p.position(); pos > 0 {
+		p.order[0], p.order[pos] = p.order[pos], p.order[0]
+	}
+}
@@ -0,0 +1,131 @@
+package playlist
+
+import (
+	"errors"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePlayer records the opened locations.
+type fakePlayer struct {
+	opened []string
+}
+
+func (f *fakePlayer) Open(u fyne.URI) error {
+	f.opened = append(f.opened, u.Name())
+	return nil
+}
+
+func (f *fakePlayer) Play() error {
+	return nil
+}
+
+func newTestPlaylist(names ...string) (*Playlist, *fakePlayer) {
+	uris := make([]fyne.URI, len(names))
+	for i, name := range names {
+		uris[i] = storage.NewFileURI("/tmp/" + name)
+	}
+	player := &fakePlayer{}
+	p := New(uris...)
+	p.Attach(player)
+	return p, player
+}
+
+func TestPlaylistNavigation(t *testing.T) {
+	p, player := newTestPlaylist("a.ogv", "b.ogv", "c.ogv")
+	assert.Equal(t, 3, p.Len())
+	assert.Equal(t, -1, p.Current())
+
+	var current []int
+	p.SetOnCurrentChanged(func(index int, entry Entry) {
+		current = append(current, index)
+	})
+
+	assert.Nil(t, p.Next())
+	assert.Nil(t, p.Next())
+	assert.Nil(t, p.Previous())
+	assert.Equal(t, []int{0, 1, 0}, current)
+	assert.Equal(t, []string{"a.ogv", "b.ogv", "a.ogv"}, player.opened)
+
+	err := p.Previous()
+	assert.True(t, errors.Is(err, streamer.ErrEndOfPlaylist))
+
+	err = p.SetCurrent(3)
+	assert.True(t, errors.Is(err, streamer.ErrInvalidEntry))
+
+	assert.Nil(t, p.SetCurrent(2))
+	assert.False(t, p.Advance())
+
+	p.SetRepeat(RepeatAll)
+	assert.True(t, p.Advance())
+	assert.Equal(t, 0, p.Current())
+
+	p.SetRepeat(RepeatOne)
+	assert.True(t, p.Advance())
+	assert.Equal(t, 0, p.Current())
+	// the user can leave the repeated entry
+	assert.Nil(t, p.Next())
+	assert.Equal(t, 1, p.Current())
+}
+
+func TestPlaylistDetach(t *testing.T) {
+	p, player := newTestPlaylist("a.ogv", "b.ogv")
+
+	// another player doesn't detach the attached one
+	p.Detach(&fakePlayer{})
+	assert.Nil(t, p.Next())
+	assert.Equal(t, []string{"a.ogv"}, player.opened)
+
+	p.Detach(player)
+	err := p.Next()
+	assert.True(t, errors.Is(err, streamer.ErrNoPlayer))
+	assert.False(t, p.Advance())
+	assert.Equal(t, []string{"a.ogv"}, player.opened)
+}
+
+func TestPlaylistEdition(t *testing.T) {
+	p, _ := newTestPlaylist("a.ogv", "b.ogv", "c.ogv", "d.ogv")
+	changes := 0
+	p.SetOnChanged(func() {
+		changes++
+	})
+
+	assert.Nil(t, p.SetCurrent(1))
+	assert.Nil(t, p.Move(1, 3))
+	assert.Equal(t, 3, p.Current())
+	assert.Equal(t, "b.ogv", p.Entries()[3].Name())
+
+	assert.Nil(t, p.Remove(0))
+	assert.Equal(t, 2, p.Current())
+	assert.Equal(t, 3, p.Len())
+
+	assert.Nil(t, p.Remove(2))
+	assert.Equal(t, -1, p.Current())
+
+	err := p.Move(0, 5)
+	assert.True(t, errors.Is(err, streamer.ErrInvalidEntry))
+
+	p.Clear()
+	assert.Equal(t, 0, p.Len())
+	assert.Equal(t, 4, changes)
+
+	detached := New(storage.NewFileURI("/tmp/a.ogv"))
+	err = detached.SetCurrent(0)
+	assert.True(t, errors.Is(err, streamer.ErrNoPlayer))
+}
+
+func TestPlaylistShuffle(t *testing.T) {
+	p, player := newTestPlaylist("a.ogv", "b.ogv", "c.ogv", "d.ogv", "e.ogv")
+	p.SetShuffle(true)
+	assert.True(t, p.Shuffle())
+
+	// each entry is played once
+	assert.Nil(t, p.Next())
+	for p.Advance() {
+	}
+	assert.ElementsMatch(t, []string{"a.ogv", "b.ogv", "c.ogv", "d.ogv", "e.ogv"}, player.opened)
+}
@@ -0,0 +1,123 @@
+package playlist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// parsePLS reads a PLS file, an ini file with numbered keys, e.g.:
+//
+//	[playlist]
+//	File1=http://example.com/stream
+//	Title1=My radio
+//	Length1=-1
+//	NumberOfEntries=1
+//	Version=2
+//
+// The entries are ordered by number, the missing numbers are ignored.
+func parsePLS(r io.Reader, base fyne.URI) ([]Entry, error) {
+	entries := map[int]*Entry{}
+	entry := func(n int) *Entry {
+		if entries[n] == nil {
+			entries[n] = &Entry{}
+		}
+		return entries[n]
+	}
+
+	inPlaylist := false
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" || strings.HasPrefix(text, ";") {
+			continue
+		}
+		if strings.HasPrefix(text, "[") {
+			inPlaylist = strings.EqualFold(text, "[playlist]")
+			continue
+		}
+		i := strings.Index(text, "=")
+		if !inPlaylist || i < 0 {
+			continue
+		}
+		key, value := strings.ToLower(strings.TrimSpace(text[:i])), strings.TrimSpace(text[i+1:])
+
+		var name string
+		switch {
+		case strings.HasPrefix(key, "file"):
+			name = "file"
+		case strings.HasPrefix(key, "title"):
+			name = "title"
+		case strings.HasPrefix(key, "length"):
+			name = "length"
+		default:
+			// NumberOfEntries and Version are not needed
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(key, name))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid key %q", streamer.ErrInvalidPlaylist, line, key)
+		}
+		switch name {
+		case "file":
+			u, err := resolveLocation(value, base)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", streamer.ErrInvalidPlaylist, line, err)
+			}
+			entry(n).URI = u
+		case "title":
+			entry(n).Title = value
+		case "length":
+			if length, err := strconv.Atoi(value); err == nil && length > 0 {
+				entry(n).Duration = time.Duration(length) * time.Second
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	numbers := make([]int, 0, len(entries))
+	for n, entry := range entries {
+		if entry.URI != nil {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+	list := make([]Entry, len(numbers))
+	for i, n := range numbers {
+		list[i] = *entries[n]
+	}
+	return list, nil
+}
+
+// writePLS writes a PLS file, version 2.
+func writePLS(w io.Writer, entries []Entry) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintln(writer, "[playlist]")
+	n := 0
+	for _, entry := range entries {
+		if entry.URI == nil {
+			continue
+		}
+		n++
+		fmt.Fprintf(writer, "File%d=%s\n", n, writtenLocation(entry.URI))
+		if entry.Title != "" {
+			fmt.Fprintf(writer, "Title%d=%s\n", n, entry.Title)
+		}
+		fmt.Fprintf(writer, "Length%d=%d\n", n, seconds(entry.Duration))
+	}
+	fmt.Fprintf(writer, "NumberOfEntries=%d\n", n)
+	fmt.Fprintln(writer, "Version=2")
+	return writer.Flush()
+}
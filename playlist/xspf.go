@@ -0,0 +1,89 @@
+package playlist
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"fyne.io/fyne/v2"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// xspfNamespace is the XML namespace of the XSPF files, version 1.
+const xspfNamespace = "http://xspf.org/ns/0/"
+
+// xspfPlaylist is the root element of a XSPF file. Only the elements used by the entries are read.
+type xspfPlaylist struct {
+	XMLName   xml.Name    `xml:"playlist"`
+	Namespace string      `xml:"xmlns,attr"`
+	Version   string      `xml:"version,attr"`
+	Tracks    []xspfTrack `xml:"trackList>track"`
+}
+
+// xspfTrack is a track of a XSPF file. There can be several locations, the first one is used.
+type xspfTrack struct {
+	Locations []string `xml:"location"`
+	Title     string   `xml:"title,omitempty"`
+	Creator   string   `xml:"creator,omitempty"`
+	Duration  int64    `xml:"duration,omitempty"` // in milliseconds
+}
+
+// parseXSPF reads a XSPF file.
+func parseXSPF(r io.Reader, base fyne.URI) ([]Entry, error) {
+	var playlist xspfPlaylist
+	if err := xml.NewDecoder(r).Decode(&playlist); err != nil {
+		return nil, fmt.Errorf("%w: %v", streamer.ErrInvalidPlaylist, err)
+	}
+
+	var entries []Entry
+	for _, track := range playlist.Tracks {
+		if len(track.Locations) == 0 {
+			continue
+		}
+		u, err := resolveLocation(track.Locations[0], base)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", streamer.ErrInvalidPlaylist, err)
+		}
+		entry := Entry{
+			URI:      u,
+			Title:    track.Title,
+			Duration: time.Duration(track.Duration) * time.Millisecond,
+		}
+		if track.Creator != "" && track.Title != "" {
+			entry.Title = track.Creator + " - " + track.Title
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// writeXSPF writes a XSPF file, version 1.
+func writeXSPF(w io.Writer, entries []Entry) error {
+	playlist := xspfPlaylist{
+		Namespace: xspfNamespace,
+		Version:   "1",
+		Tracks:    make([]xspfTrack, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		if entry.URI == nil {
+			continue
+		}
+		playlist.Tracks = append(playlist.Tracks, xspfTrack{
+			Locations: []string{escapedLocation(entry.URI)},
+			Title:     entry.Title,
+			Duration:  entry.Duration.Milliseconds(),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(playlist); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,18 @@
+/*
+Package thumbnail generates thumbnails of video files or streams, without any widget or window.
+
+A hidden pipeline is created on the given location (file or http(s) URI), with no audio output,
+and it is seeked to each requested position to get the frames. The frames are scaled to the
+requested size, black borders are added to keep the aspect ratio.
+
+For example, to get 5 thumbnails evenly spaced in a video:
+
+	images, err := thumbnail.Generate(storage.NewFileURI("/path/to/video.mp4"), 5, fyne.NewSize(160, 90))
+
+Or at some positions:
+
+	images, err := thumbnail.GenerateAt(uri, []time.Duration{time.Second, time.Minute}, fyne.NewSize(160, 90))
+
+The images can be displayed with canvas.NewImageFromImage().
+*/
+package thumbnail
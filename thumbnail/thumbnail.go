@@ -0,0 +1,68 @@
+package thumbnail
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/metal3d/fyne-streamer/internal/grabber"
+)
+
+// Timeout is the time to wait for each frame before giving up.
+var Timeout = grabber.DefaultTimeout
+
+// Generate returns "count" thumbnails, evenly spaced in the media, scaled to the given size.
+// The first and the last frames are not used as they are often black.
+func Generate(u fyne.URI, count int, size fyne.Size) ([]image.Image, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("the number of thumbnails should be positive, given value %d", count)
+	}
+
+	g, err := newGrabber(u, size)
+	if err != nil {
+		return nil, err
+	}
+	defer g.Close()
+
+	duration, err := g.Duration()
+	if err != nil {
+		return nil, err
+	}
+	positions := make([]time.Duration, count)
+	for i := range positions {
+		positions[i] = duration * time.Duration(i+1) / time.Duration(count+1)
+	}
+	return grab(g, positions)
+}
+
+// GenerateAt returns the thumbnails at the given positions, scaled to the given size.
+func GenerateAt(u fyne.URI, positions []time.Duration, size fyne.Size) ([]image.Image, error) {
+	g, err := newGrabber(u, size)
+	if err != nil {
+		return nil, err
+	}
+	defer g.Close()
+	return grab(g, positions)
+}
+
+// newGrabber creates the hidden pipeline for the given size.
+func newGrabber(u fyne.URI, size fyne.Size) (*grabber.Grabber, error) {
+	if size.Width < 1 || size.Height < 1 {
+		return nil, fmt.Errorf("invalid thumbnail size %v", size)
+	}
+	return grabber.New(u, int(size.Width), int(size.Height), Timeout)
+}
+
+// grab gets the frames at the given positions.
+func grab(g *grabber.Grabber, positions []time.Duration) ([]image.Image, error) {
+	images := make([]image.Image, len(positions))
+	for i, pos := range positions {
+		img, err := g.FrameAt(pos)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the thumbnail at %v: %w", pos, err)
+		}
+		images[i] = img
+	}
+	return images, nil
+}
@@ -0,0 +1,32 @@
+package thumbnail
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+const _testVideoFile = "../video/test-files/testvideo.ogv"
+
+func TestGenerate(t *testing.T) {
+	images, err := Generate(storage.NewFileURI(_testVideoFile), 3, fyne.NewSize(160, 90))
+	assert.Nil(t, err)
+	assert.Len(t, images, 3)
+	for _, img := range images {
+		assert.Equal(t, 160, img.Bounds().Dx())
+		assert.Equal(t, 90, img.Bounds().Dy())
+	}
+}
+
+func TestGenerateAt(t *testing.T) {
+	positions := []time.Duration{0, time.Second}
+	images, err := GenerateAt(storage.NewFileURI(_testVideoFile), positions, fyne.NewSize(64, 64))
+	assert.Nil(t, err)
+	assert.Len(t, images, len(positions))
+
+	_, err = GenerateAt(storage.NewFileURI(_testVideoFile), positions, fyne.NewSize(0, 0))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,121 @@
+package video
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+)
+
+// chapterRestartDelay is the time after the start of a chapter where PreviousChapter goes to the
+// previous chapter. After that, it goes back to the start of the current chapter.
+const chapterRestartDelay = 3 * time.Second
+
+// Chapter is a chapter of the media, read from the table of contents of the container
+// (e.g. Matroska or MP4 chapters).
+type Chapter struct {
+	Title string // empty if the chapter has no title
+	Start time.Duration
+	End   time.Duration // the start of the next chapter if the container doesn't give it
+}
+
+// Chapters returns the chapters of the media, ordered by start time. The chapters are found
+// while the pipeline is prerolling, use SetOnChaptersChanged to be notified.
+func (v *Viewer) Chapters() []Chapter {
+	v.chapterLock.Lock()
+	defer v.chapterLock.Unlock()
+	return append([]Chapter(nil), v.chapters...)
+}
+
+// NextChapter seeks to the start of the chapter after the current position.
+func (v *Viewer) NextChapter() error {
+	pos, err := v.CurrentPosition()
+	if err != nil {
+		return err
+	}
+	chapters := v.Chapters()
+	for i, chapter := range chapters {
+		if chapter.Start > pos {
+			return v.SeekToChapter(i)
+		}
+	}
+	return fmt.Errorf("%w: no chapter after %v", streamer.ErrInvalidChapter, pos)
+}
+
+// PreviousChapter seeks to the start of the current chapter, or to the start of the previous
+// chapter if the current one has just started.
+func (v *Viewer) PreviousChapter() error {
+	pos, err := v.CurrentPosition()
+	if err != nil {
+		return err
+	}
+	chapters := v.Chapters()
+	if len(chapters) == 0 {
+		return fmt.Errorf("%w: there is no chapter", streamer.ErrInvalidChapter)
+	}
+	target := 0
+	for i, chapter := range chapters {
+		if chapter.Start+chapterRestartDelay < pos {
+			target = i
+		}
+	}
+	return v.SeekToChapter(target)
+}
+
+// SeekToChapter seeks to the start of the chapter at the given index (see Chapters).
+func (v *Viewer) SeekToChapter(i int) error {
+	chapters := v.Chapters()
+	if i < 0 || i >= len(chapters) {
+		return fmt.Errorf("%w: %d, there are %d chapters", streamer.ErrInvalidChapter, i, len(chapters))
+	}
+	return v.Seek(chapters[i].Start)
+}
+
+// chapterAt returns the chapter at the given position, ok is false if there is none.
+func (v *Viewer) chapterAt(pos time.Duration) (Chapter, bool) {
+	v.chapterLock.Lock()
+	defer v.chapterLock.Unlock()
+	for _, chapter := range v.chapters {
+		if pos >= chapter.Start && (pos < chapter.End || chapter.End == 0) {
+			return chapter, true
+		}
+	}
+	return Chapter{}, false
+}
+
+// handleTOCMessage reads the chapters of the table of contents posted by the demuxer.
+func (v *Viewer) handleTOCMessage(msg *gst.Message) {
+	toc, _ := msg.ParseTOC()
+	entries := utils.TOCChapters(toc)
+
+	chapters := make([]Chapter, len(entries))
+	for i, entry := range entries {
+		chapters[i] = Chapter{Title: entry.Title, Start: entry.Start, End: entry.End}
+	}
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].Start < chapters[j].Start
+	})
+	// the missing ends are the start of the next chapter, or the end of the media
+	duration, _ := v.Duration()
+	for i := range chapters {
+		switch {
+		case chapters[i].End > 0:
+			// given by the container
+		case i+1 < len(chapters):
+			chapters[i].End = chapters[i+1].Start
+		default:
+			chapters[i].End = duration
+		}
+	}
+
+	v.chapterLock.Lock()
+	v.chapters = chapters
+	v.chapterLock.Unlock()
+
+	if v.onChapters != nil {
+		v.onChapters(v.Chapters())
+	}
+}
@@ -0,0 +1,93 @@
+package video
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestTOC creates a table of contents with an edition of chapters, as Matroska does.
+// The last chapter has no end.
+func newTestTOC(titles []string, starts []time.Duration) *gst.TOC {
+	edition := gst.NewTOCEntry(gst.TOCEntryTypeEdition, "edition")
+	for i, title := range titles {
+		chapter := gst.NewTOCEntry(gst.TOCEntryTypeChapter, title)
+		tags := gst.NewEmptyTagList()
+		tags.AddValue(gst.TagMergeReplace, gst.TagTitle, title)
+		chapter.SetTags(tags)
+		end := int64(-1)
+		if i+1 < len(starts) {
+			end = int64(starts[i+1])
+		}
+		chapter.SetStartStopTimes(int64(starts[i]), end)
+		edition.AppendSubEntry(chapter)
+	}
+	toc := gst.NewTOC(gst.TOCScopeGlobal)
+	toc.AppendEntry(edition.Ref()) // the toc takes the reference
+	return toc
+}
+
+func TestChapters(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+
+	err := video.SetPipelineFromString(`
+    videotestsrc num-buffers=300 ! video/x-raw,width=160,height=120,framerate=30/1 !
+    videoconvert ! video/x-raw,format=RGBA !
+    appsink name={{ .AppSinkElementName }}`)
+	assert.Nil(t, err)
+	assert.Empty(t, video.Chapters())
+
+	err = video.Pause()
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+
+	var changed []Chapter
+	video.SetOnChaptersChanged(func(chapters []Chapter) {
+		changed = chapters
+	})
+
+	toc := newTestTOC([]string{"Intro", "Middle", "Outro"}, []time.Duration{0, 3 * time.Second, 6 * time.Second})
+	video.handleTOCMessage(gst.NewTOCMessage(video.pipeline, toc, false))
+
+	chapters := video.Chapters()
+	assert.Len(t, chapters, 3)
+	assert.Equal(t, changed, chapters)
+	assert.Equal(t, Chapter{Title: "Middle", Start: 3 * time.Second, End: 6 * time.Second}, chapters[1])
+	// the end of the last chapter is the end of the media
+	assert.Equal(t, 10*time.Second, chapters[2].End)
+
+	chapter, ok := video.chapterAt(4 * time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, "Middle", chapter.Title)
+
+	err = video.SeekToChapter(3)
+	assert.True(t, errors.Is(err, streamer.ErrInvalidChapter))
+
+	err = video.NextChapter()
+	assert.Nil(t, err)
+	time.Sleep(200 * time.Millisecond)
+	pos, err := video.CurrentPosition()
+	assert.Nil(t, err)
+	assert.Equal(t, 3*time.Second, pos.Truncate(time.Second))
+
+	err = video.SeekToChapter(2)
+	assert.Nil(t, err)
+	time.Sleep(200 * time.Millisecond)
+	err = video.NextChapter()
+	assert.True(t, errors.Is(err, streamer.ErrInvalidChapter))
+
+	// the chapter has just started, so it goes to the previous one
+	err = video.PreviousChapter()
+	assert.Nil(t, err)
+	time.Sleep(200 * time.Millisecond)
+	pos, err = video.CurrentPosition()
+	assert.Nil(t, err)
+	assert.Equal(t, 3*time.Second, pos.Truncate(time.Second))
+}
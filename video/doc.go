@@ -19,11 +19,82 @@ If you need a video player with controls, use the Player widget.
 Both widgets can be fullscreened and have Play(), Pause() and Seek(duration) methods.
 The difference is that the Player has controls (auto hidden) and react on tap and double tap.
 
+External subtitles (SRT or WebVTT) can be displayed over the video, after opening it:
+
+	player := video.NewPlayer()
+	player.Open(uri)
+	player.LoadSubtitles(storage.NewFileURI("/path/to/subtitles.srt"))
+	player.SetSubtitleStyle(video.SubtitleStyle{TextSize: 24, Color: color.White})
+	player.SetSubtitleOffset(500 * time.Millisecond) // displayed later
+
+The text subtitle tracks of the media (e.g. in MKV or MP4 files) are listed by SubtitleTracks. They
+are disabled by default, SelectSubtitleTrack displays one of them with the same style, and the
+controls of the Player have a menu to choose it.
+
+The chapters of the container (e.g. Matroska or MP4 chapters) are given by Chapters, and
+SeekToChapter, NextChapter and PreviousChapter navigate between them. The position slider of
+the Player marks the chapters and shows their title when it is hovered.
+
+The tags of the media (title, artist, codecs, cover image...) are accumulated in a
+streamer.Metadata, returned by Metadata and given to the SetOnMetadata callback.
+
+The HLS (".m3u8") and DASH (".mpd") URLs are played as adaptive streams: the variant follows the
+connection speed, or the one chosen in Variants with SetVariant is kept until SetAutoVariant. The
+manifest is read in the background, SetOnVariantsChanged is called when the variants are known.
+
+The RTSP locations (e.g. IP cameras) are live streams, that can't be seeked and have no duration.
+The latency, the transport and the credentials are given to OpenWithOptions:
+
+	player.OpenWithOptions(uri, &video.OpenOptions{
+		Latency:   200 * time.Millisecond,
+		Transport: video.TransportTCP,
+		User:      "admin",
+		Password:  "secret",
+	})
+
+The MPEG-TS streams in UDP packets ("udp://239.0.0.1:1234") and the RTP streams
+("rtp://239.0.0.1:5004") are live too. The multicast groups are joined on the MulticastInterface
+of the options, and the EncodingName (H264 by default) or the Caps of the RTP packets are given
+to OpenWithOptions.
+
+The locations of the other schemes are read from their fyne storage repository, and OpenReader
+plays the data of an io.ReadSeeker, e.g. a file of an embed.FS:
+
+	file, _ := media.Open("videos/intro.webm")
+	player.OpenReader(file.(io.ReadSeeker), "intro.webm")
+
+The cameras listed by streamer.ListVideoDevices are opened with the location returned by
+DeviceURI, e.g. "device:///dev/video0?width=1280&height=720&framerate=30%2F1", at one of the
+sizes and frame rates of their Formats:
+
+	devices := streamer.ListVideoDevices()
+	uri, _ := video.DeviceURI(devices[0], 1280, 720, streamer.FrameRate{Num: 30, Den: 1})
+	player.Open(uri)
+
+Other schemes are added with RegisterOpener, whose function returns the pipeline template of a
+location, as the ones of SetPipelineFromString:
+
+	video.RegisterOpener("test", func(u fyne.URI, options *video.OpenOptions) (string, error) {
+		return `videotestsrc name={{ .InputElementName }} pattern=ball ! videoconvert !
+		video/x-raw,format=RGBA ! appsink name={{ .AppSinkElementName }}`, nil
+	})
+
+StartRecording records what is displayed (and the audio) in a WebM, Matroska or MP4 file while
+the playback continues, and StopRecording finalizes the file:
+
+	player.StartRecording("/tmp/capture.webm", video.RecordOptions{VideoBitrate: 2000})
+	// ...
+	player.StopRecording()
+
+A Viewer or a Player can be attached to a playlist.Playlist with SetPlaylist, to play its entries
+one after the other. Opening a M3U, PLS or XSPF file fills the playlist.
+
 You can create your own Gstreamer pipeline and use the Viewer widget to display the video frames.
 The mandatory element to create is an "appsink" that is name with "AppSinkElementName" (constant).
-Others names can be provided to let the player adapt the framerate, the quality of the
-image compression, etc. The encoders can be "jpegenc" or "pngenc" and it's mandatory to
-have one of them in the pipeline before the appsink element.
+Others names can be provided to let the player adapt the framerate, the video balance, etc.
+The appsink element should receive raw frames using "video/x-raw,format=RGBA" caps. For
+compatibility, the appsink can also receive images encoded by "jpegenc" or "pngenc" (legacy mode),
+but the frames are then encoded and decoded again, which is much slower.
 
 To ease the creation of the pipeline, the CustomFromString method accepts a template string with
 comments. The ElementMap variable is used as data for the template. So you can use the
@@ -37,8 +108,8 @@ For example:
 	videoconvert !
 	videorate name={{ .VideoRateElementName }} !
 
-	# add an image encoder here
-	jpegenc name={{ .ImageEncoderElementName }} !
+	# the appsink receives raw RGBA frames
+	video/x-raw,format=RGBA !
 
 	# the appsink element is mandatory
 	appsink name={{ .AppSinkElementName }} sync=true
@@ -0,0 +1,90 @@
+package video
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"sync"
+
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+)
+
+// framePoolSize is the number of frames kept by the framePool. One frame is displayed,
+// one can still be used by the canvas that is refreshing and the last one is filled with
+// the new sample.
+const framePoolSize = 3
+
+// framePool is a ring of images that are reused between samples to avoid
+// an allocation (and the garbage collection) of each frame.
+//
+// A nil framePool is valid, each frame is then a new allocated image.
+type framePool struct {
+	mu     sync.Mutex
+	frames [framePoolSize]*image.NRGBA
+	index  int
+}
+
+// newFramePool returns a new and empty framePool. The images are allocated on demand.
+func newFramePool() *framePool {
+	return &framePool{}
+}
+
+// get returns the next image of the ring, with the given size. The image is reallocated
+// if the size changed.
+func (p *framePool) get(width, height int) *image.NRGBA {
+	rect := image.Rect(0, 0, width, height)
+	if p == nil {
+		return image.NewNRGBA(rect)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	img := p.frames[p.index]
+	if img == nil || img.Rect != rect {
+		img = image.NewNRGBA(rect)
+		p.frames[p.index] = img
+	}
+	p.index = (p.index + 1) % framePoolSize
+	return img
+}
+
+// reset releases the images, e.g. when the pipeline changes.
+func (p *framePool) reset() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.frames = [framePoolSize]*image.NRGBA{}
+	p.index = 0
+}
+
+// rawFrame copies a raw video buffer to the next image of the pool. The data can be
+// released after the call.
+//
+// Only the RGBA format (non premultiplied alpha) is supported, it gives an image.NRGBA.
+func (p *framePool) rawFrame(format string, data []byte, width, height int) (image.Image, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid frame size %dx%d", width, height)
+	}
+
+	switch format {
+	case streamer.RawVideoFormat:
+		img := p.get(width, height)
+		if err := utils.CopyRawFrame(img, data); err != nil {
+			return nil, err
+		}
+		return img, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", streamer.ErrUnsupportedRawFormat, format)
+	}
+}
+
+// encodedFrameToImage decodes a jpeg or png encoded frame. This is the legacy mode
+// when the pipeline has an image encoder before the appsink element.
+func encodedFrameToImage(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
@@ -1,7 +1,6 @@
 package video
 
 import (
-	"bytes"
 	"fmt"
 	"image"
 	"image/color"
@@ -20,6 +19,13 @@ import (
 	"github.com/metal3d/fyne-streamer/internal/utils"
 )
 
+// formatBuffers is the GST_FORMAT_BUFFERS format that is not exported by go-gst.
+// It is used to step the video frame by frame.
+const formatBuffers = gst.Format(4)
+
+// defaultMaxRate is the max frame rate of the viewers, see SetMaxRate.
+const defaultMaxRate = 30
+
 // prerollFunc is called when the pipeline is prerolling. This is a callback on the appsink.
 func (v *Viewer) prerollFunc(appSink *app.Sink) gst.FlowReturn {
 	caps, err := appSink.Element.GetPads()
@@ -28,12 +34,26 @@ func (v *Viewer) prerollFunc(appSink *app.Sink) gst.FlowReturn {
 	}
 
 	c := caps[0].GetCurrentCaps()
-	ww, err := c.GetStructureAt(0).GetValue("width")
+	structure := c.GetStructureAt(0)
+
+	// raw frames are used as is, others are encoded images (legacy mode)
+	v.frameFormat = ""
+	if structure.Name() == "video/x-raw" {
+		format, err := structure.GetValue("format")
+		if err != nil {
+			log.Printf("Error: %v", err)
+		}
+		if format, ok := format.(string); ok {
+			v.frameFormat = format
+		}
+	}
+
+	ww, err := structure.GetValue("width")
 	if err != nil {
 		log.Printf("Error: %v", err)
 	}
 
-	hh, err := c.GetStructureAt(0).GetValue("height")
+	hh, err := structure.GetValue("height")
 	if err != nil {
 		log.Printf("Error: %v", err)
 	}
@@ -46,6 +66,21 @@ func (v *Viewer) prerollFunc(appSink *app.Sink) gst.FlowReturn {
 		v.height = hh
 	}
 
+	// the framerate is 0/1 for variable framerate streams
+	v.frameRate = 0
+	if fr, err := structure.GetValue("framerate"); err == nil {
+		if fr, ok := fr.(*gst.FractionValue); ok && fr.Denom() != 0 {
+			v.frameRate = float64(fr.Num()) / float64(fr.Denom())
+		}
+	}
+
+	// the adaptive demuxer is plugged when the stream starts
+	if v.SelectedVariant() >= 0 {
+		if err := v.applyVariant(); err != nil {
+			fyne.LogError("Failed to select the variant", err)
+		}
+	}
+
 	// call the callback
 	if v.onPreRoll != nil {
 		v.onPreRoll()
@@ -54,6 +89,14 @@ func (v *Viewer) prerollFunc(appSink *app.Sink) gst.FlowReturn {
 }
 
 func (v *Viewer) reset() {
+	v.recordingLock.Lock()
+	if v.recording != nil {
+		if err := v.finishRecording(v.recording); err != nil {
+			fyne.LogError("Failed to finalize the recording", err)
+		}
+		v.recording = nil
+	}
+	v.recordingLock.Unlock()
 	if v.pipeline != nil {
 		//BUG: do not use v.SetState(gst.StateNull)
 		if err := v.pipeline.SetState(gst.StateNull); err != nil {
@@ -62,7 +105,42 @@ func (v *Viewer) reset() {
 			v.pipeline.Clear()
 		}
 	}
+	if v.reader != nil {
+		if err := v.reader.Close(); err != nil {
+			fyne.LogError("Failed to close the media reader", err)
+		}
+		v.reader = nil
+	}
+	v.frameLock.Lock()
 	v.frame.Image = nil
+	v.frameLock.Unlock()
+	v.subtitleLock.Lock()
+	v.embeddedCues = nil
+	v.subtitleLock.Unlock()
+	v.ClearSubtitles()
+	v.chapterLock.Lock()
+	v.chapters = nil
+	v.chapterLock.Unlock()
+	v.metadataLock.Lock()
+	v.metadata = streamer.Metadata{}
+	v.metadataLock.Unlock()
+	v.framePool.reset()
+	v.uri = nil
+	v.trackLock.Lock()
+	v.audioTracks = nil
+	v.audioTrack = 0
+	v.subtitleTracks = nil
+	v.subtitleTrack = -1
+	v.trackLock.Unlock()
+	v.variantLock.Lock()
+	v.variants = nil
+	v.variant = -1
+	v.variantFetch++
+	v.variantLock.Unlock()
+	v.setLive(false)
+	v.frameFormat = ""
+	v.frameRate = 0
+	v.playbackRate = 1
 	v.duration = 0
 }
 
@@ -80,11 +158,6 @@ func (v *Viewer) registerElements() error {
 		log.Printf("Warning: Failed to find "+streamer.VideoRateElementName+" element: %v", err)
 	}
 
-	_, err = v.pipeline.GetElementByName(streamer.ImageEncoderElementName)
-	if err != nil {
-		log.Printf("Warning: Failed to find "+streamer.ImageEncoderElementName+" element: %v", err)
-	}
-
 	_, err = v.pipeline.GetElementByName(streamer.DecodeElementName)
 	if err != nil {
 		log.Printf("Warning: Failed to find "+streamer.DecodeElementName+" element: %v", err)
@@ -95,6 +168,18 @@ func (v *Viewer) registerElements() error {
 		fyne.LogError("Failed to find the appsink element", err)
 		return fmt.Errorf("Failed to find the mandatory %s element %w", streamer.AppSinkElementName, err)
 	}
+	v.registerTrackSelectors()
+
+	// the spectrum is optional, apply the settings if there is one
+	if v.spectrumBands > 0 || v.spectrumInterval > 0 {
+		if err := utils.ConfigureSpectrum(v.pipeline, v.spectrumBands, v.spectrumInterval); err != nil && err != streamer.ErrNoSpectrum {
+			fyne.LogError("Failed to configure the spectrum", err)
+		}
+	}
+
+	// the spectrum and the level only post messages if they are listened
+	v.postAudioMessages()
+
 	v.appSink = app.SinkFromElement(appelement)
 	v.appSink.SetCallbacks(&app.SinkCallbacks{
 		EOSFunc:        v.eosFunc,
@@ -102,6 +187,13 @@ func (v *Viewer) registerElements() error {
 		NewSampleFunc:  v.newSampleFunc,
 	})
 
+	// the embedded subtitles are optional
+	if subtitleElement, err := v.pipeline.GetElementByName(streamer.SubtitleSinkElementName); err == nil && subtitleElement != nil {
+		app.SinkFromElement(subtitleElement).SetCallbacks(&app.SinkCallbacks{
+			NewSampleFunc: v.newSubtitleFunc,
+		})
+	}
+
 	return nil
 }
 
@@ -110,27 +202,136 @@ func (v *Viewer) eosFunc(appSink *app.Sink) {
 	if v.onEOS != nil {
 		v.onEOS()
 	}
+	if v.playlist != nil {
+		// the next entry can't be opened from the streaming thread, that is stopped by Open
+		go func() {
+			if !v.playlist.Advance() {
+				v.rewind()
+			}
+		}()
+		return
+	}
+	v.rewind()
+}
+
+// setLive marks the source as live, or not.
+func (v *Viewer) setLive(live bool) {
+	v.liveLock.Lock()
+	defer v.liveLock.Unlock()
+	v.live = live
+}
+
+// rewind pauses the pipeline and goes back to the beginning, so that the video can be restarted.
+// When the stream is played backward, the beginning is the end of the stream: seeking to 0 would
+// make an empty segment that ends the stream again as soon as it is played.
+func (v *Viewer) rewind() {
 	err := v.SetState(gst.StatePaused)
 	if err != nil {
 		fyne.LogError("Failed to set pipeline to paused", err)
 	}
+	var start time.Duration
+	if v.playbackRate < 0 {
+		if duration, err := v.Duration(); err == nil {
+			start = duration
+		} else {
+			v.playbackRate = 1
+		}
+	}
 	// TODO: this is a workaround to avoid a crash when the pipeline is stopped and to make it restartable.
 	time.AfterFunc(time.Millisecond*100, func() {
-		v.Seek(0)
-		time.AfterFunc(time.Millisecond*100, func() {
-			img, err := v.getCurrentFrame(v.appSink, true)
-			if err != gst.FlowOK {
-				log.Println("error getting first frame", err)
-				return
-			}
-			v.frame.Image = img
-			v.frame.Refresh()
-		})
+		v.Seek(start)
+		time.AfterFunc(time.Millisecond*100, v.refreshLastFrame)
 	})
 }
 
+// handleTagMessage adds the tags of the message to the metadata, and calls the title and
+// metadata callbacks.
+func (v *Viewer) handleTagMessage(msg *gst.Message) {
+	tags := msg.ParseTags()
+	if tags == nil {
+		return
+	}
+	title, ok := tags.GetString(gst.TagTitle)
+	if v.onTitle != nil && ok {
+		v.onTitle(title)
+	}
+
+	v.metadataLock.Lock()
+	v.metadata.AddTags(tags)
+	metadata := v.metadata
+	v.metadataLock.Unlock()
+
+	if v.onMetadata != nil {
+		v.onMetadata(metadata)
+	}
+}
+
+// handleElementMessage dispatches the messages posted on the bus by the elements of the pipeline.
+func (v *Viewer) handleElementMessage(msg *gst.Message) {
+	structure := msg.GetStructure()
+	if structure == nil {
+		return
+	}
+	switch {
+	case v.onSpectrum != nil && structure.Name() == "spectrum":
+		if magnitudes := utils.SpectrumMagnitudes(structure); magnitudes != nil {
+			v.onSpectrum(magnitudes)
+		}
+	case (v.onAudioLevel != nil || v.onLevelMeter != nil) && structure.Name() == "level":
+		rms, peak := utils.AudioLevels(structure)
+		if rms == nil {
+			return
+		}
+		if v.onAudioLevel != nil {
+			v.onAudioLevel(rms, peak)
+		}
+		if v.onLevelMeter != nil {
+			v.onLevelMeter(rms, peak)
+		}
+	}
+}
+
+// postAudioMessages enables the messages of the spectrum and the level elements that are listened.
+func (v *Viewer) postAudioMessages() {
+	utils.PostAudioMessages(v.pipeline, v.onSpectrum != nil, v.onAudioLevel != nil || v.onLevelMeter != nil)
+}
+
+// setOnLevelMeter sets the function that feeds the level meter of the controls. It is called
+// with the SetOnAudioLevel function, that is kept.
+func (v *Viewer) setOnLevelMeter(f func(rms, peak []float64)) {
+	v.onLevelMeter = f
+	v.postAudioMessages()
+}
+
+// refreshLastFrame displays the last sample of the appsink. It is used when the pipeline
+// is not playing, e.g. after a seek or a frame step.
+func (v *Viewer) refreshLastFrame() {
+	if v.appSink == nil {
+		return
+	}
+	img, ret := v.getCurrentFrame(v.appSink, true)
+	if ret != gst.FlowOK {
+		log.Println("error getting the last frame", ret)
+		return
+	}
+	v.setFrame(img)
+	if pos, err := v.CurrentPosition(); err == nil {
+		v.updateSubtitles(pos)
+	}
+}
+
+// setFrame displays the given image. The image is protected by the frameLock to
+// be able to take snapshots of the displayed frame.
+func (v *Viewer) setFrame(img image.Image) {
+	v.frameLock.Lock()
+	v.frame.Image = img
+	v.frameLock.Unlock()
+	v.frame.Refresh()
+}
+
 // newSampleFunc is called when a new sample is available. This is a callback on the appsink.
-// The sample is a jpeg image and is decoded to the internal frameView canvas.Image.
+// The sample is a raw RGBA frame (or a jpeg/png image in legacy mode) and is converted to the
+// internal frameView canvas.Image.
 // It is refreshed as soon as the frame is OK.
 func (v *Viewer) newSampleFunc(appSink *app.Sink) gst.FlowReturn {
 
@@ -139,12 +340,10 @@ func (v *Viewer) newSampleFunc(appSink *app.Sink) gst.FlowReturn {
 		return ret
 	}
 
-	func() { // refresh the frame in a goroutine
-		v.frame.Image = img
-		v.frame.Refresh()
-	}()
+	v.setFrame(img)
 
 	_, pos := v.pipeline.QueryPosition(gst.FormatTime)
+	v.updateSubtitles(time.Duration(pos))
 	if v.onNewFrame != nil {
 		// get the current time of the pipeline
 		go v.onNewFrame(time.Duration(float64(pos)))
@@ -170,22 +369,58 @@ func (v *Viewer) getCurrentFrame(appSink *app.Sink, latest bool) (image.Image, g
 	}
 	defer buffer.Unmap()
 
-	samples := buffer.Map(gst.MapRead).AsUint8Slice()
+	mapInfo := buffer.Map(gst.MapRead)
+	if mapInfo == nil {
+		return nil, gst.FlowError
+	}
+	samples := utils.MappedBytes(mapInfo)
 	if samples == nil {
 		return nil, gst.FlowError
 	}
 
-	// the sample is a jpeg
-	reader := bytes.NewReader(samples)
-	img, _, err := image.Decode(reader)
+	var (
+		img image.Image
+		err error
+	)
+	if v.frameFormat != "" {
+		// the raw frame is copied to a reused image, the buffer can be unmapped after that
+		img, err = v.framePool.rawFrame(v.frameFormat, samples, v.width, v.height)
+	} else {
+		// the sample is a jpeg or png image
+		img, err = encodedFrameToImage(samples)
+	}
 	if err != nil {
-		fyne.LogError("Failed to decode image: %w", err)
+		fyne.LogError("Failed to decode image", err)
 		return nil, gst.FlowError
 	}
 
 	return img, gst.FlowOK
 }
 
+// setMaxLateness sets the max-lateness of the appsink to the duration of a frame at the max rate.
+func (v *Viewer) setMaxLateness() error {
+	if v.appSink == nil {
+		return nil
+	}
+	return v.appSink.SetProperty("max-lateness", time.Second.Nanoseconds()/int64(v.rate))
+}
+
+// seek sends a seek event to the pipeline at the given position, keeping the current playback rate.
+// With a negative rate, the stream is played from the given position to the beginning.
+func (v *Viewer) seek(pos time.Duration, flags gst.SeekFlags) bool {
+	rate := v.playbackRate
+	if rate == 0 {
+		rate = 1
+	}
+	var event *gst.Event
+	if rate > 0 {
+		event = gst.NewSeekEvent(rate, gst.FormatTime, flags, gst.SeekTypeSet, pos.Nanoseconds(), gst.SeekTypeNone, -1)
+	} else {
+		event = gst.NewSeekEvent(rate, gst.FormatTime, flags, gst.SeekTypeSet, 0, gst.SeekTypeSet, pos.Nanoseconds())
+	}
+	return v.pipeline.SendEvent(event)
+}
+
 // resync the pipeline with the parent state. This seems to fix some problem
 // on leaveing paused state. But not always...
 // At this time, this function is called on SetState and Seek methods.
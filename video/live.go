@@ -0,0 +1,86 @@
+package video
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+)
+
+// liveBranches are the video and audio branches of the live pipelines, after the decode element.
+// The audio tracks are linked to the selector by the viewer, see watchDecodedTracks.
+const liveBranches = `
+    # manage the video
+    {{ .DecodeElementName }}. !
+    queue !
+    videoconvert !
+    videoscale !
+    videorate name={{ .VideoRateElementName }} !
+    videobalance name={{ .VideoBalanceElementName }} !
+    video/x-raw,format=RGBA !
+    appsink name={{ .AppSinkElementName }} sync=true
+
+    # manage the sound, the audio tracks are linked to the selector by the viewer
+    input-selector name={{ .AudioSelectorElementName }} !
+    queue !
+    audioconvert !
+    audioresample !
+    spectrum name={{ .SpectrumElementName }} post-messages=false !
+    volume name={{ .VolumeElementName }} !
+    level name={{ .LevelElementName }} post-messages=false !
+    autoaudiosink sync=true
+    `
+
+// linkLiveSource links the streams of a source that has a pad for each stream, e.g. rtspsrc or
+// tsdemux.
+// The first video stream goes to the decode element of the pipeline, each other stream is
+// decoded by its own decodebin.
+func (v *Viewer) linkLiveSource(source *gst.Element) error {
+	decode, err := v.pipeline.GetElementByName(streamer.DecodeElementName)
+	if err != nil {
+		return err
+	}
+	_, err = source.Connect("pad-added", func(self *gst.Element, pad *gst.Pad) {
+		sinkPad := decode.GetStaticPad("sink")
+		if streamMedia(pad) == "video" && !sinkPad.IsLinked() {
+			if ret := pad.Link(sinkPad); ret != gst.PadLinkOK {
+				fyne.LogError("Failed to link the video stream", fmt.Errorf("link returned %v", ret))
+			}
+			return
+		}
+		v.decodeStream(pad)
+	})
+	return err
+}
+
+// decodeStream decodes the stream of the pad in a new decodebin, whose audio and text tracks
+// are linked to their selectors.
+func (v *Viewer) decodeStream(pad *gst.Pad) {
+	decode, err := gst.NewElement("decodebin")
+	if err != nil {
+		fyne.LogError("Failed to create a decoder for the stream", err)
+		return
+	}
+	if err := v.pipeline.Add(decode); err != nil {
+		fyne.LogError("Failed to add the decoder of the stream", err)
+		return
+	}
+	v.watchDecodedTracks(decode)
+	if ret := pad.Link(decode.GetStaticPad("sink")); ret != gst.PadLinkOK {
+		fyne.LogError("Failed to link the stream", fmt.Errorf("link returned %v", ret))
+		return
+	}
+	decode.SyncStateWithParent()
+}
+
+// streamMedia returns the kind of the stream of the pad, e.g. "video" or "audio": the "media" of
+// the RTP streams, or the type of the caps for the others.
+func streamMedia(pad *gst.Pad) string {
+	if media, ok := utils.PadCapsString(pad, "media"); ok {
+		return media
+	}
+	return strings.SplitN(utils.PadMediaType(pad), "/", 2)[0]
+}
@@ -1,11 +1,7 @@
 package video
 
 import (
-	"bytes"
-	"text/template"
-
 	"github.com/go-gst/go-gst/gst"
-	streamer "github.com/metal3d/fyne-streamer"
 	"github.com/metal3d/fyne-streamer/internal/utils"
 )
 
@@ -16,6 +12,8 @@ import (
 // It also upports comments starting with #.
 //
 // It is mandatory to provide at least an "appsink" element named with AppSinkElementName.
+// The appsink should receive raw frames with "video/x-raw,format=RGBA" caps. Jpeg or png
+// encoded images are still accepted (legacy mode) but they are slower to display.
 //
 // You can also provide other elements that the viewer will manage.
 // Use provided const names for the elements: InputElementName, DecodeElementName,
@@ -27,8 +25,8 @@ import (
 //	    autovideosrc name={{ .InputElementName }} ! # the input, a video test
 //	    videoconvert n-threads=4 ! # convert to something usable
 //	    videorate name={{ .VideoRateElementName }} max-rate=30 ! # fix the framerate
-//	    # encode to jpeg (or png), mandatory for appsink
-//	    jpegenc name={{ .ImageEncoderElementName }} quality=80 !
+//	    # raw RGBA frames for the appsink
+//	    video/x-raw,format=RGBA !
 //	    # the appsink (mandatory)
 //	    appsink name={{ .AppSinkElementName }} drop=true max-lateness=33333 sync=true
 //	    `
@@ -39,18 +37,7 @@ func (v *Viewer) SetPipelineFromString(pipeline string) error {
 
 	v.reset()
 
-	tpl, err := template.New("pipeline").Parse(pipeline)
-	if err != nil {
-		return err
-	}
-	buf := new(bytes.Buffer)
-	err = tpl.Execute(buf, streamer.ElementMap)
-	if err != nil {
-		return err
-	}
-
-	pipeline = buf.String()
-	pipelineObj, err := utils.NewPipelineFromString(pipeline)
+	pipelineObj, err := utils.NewPipelineFromTemplate(pipeline)
 	if err != nil {
 		return err
 	}
@@ -81,12 +68,12 @@ func (v *Viewer) createBus() {
 	bus := v.pipeline.GetPipelineBus()
 	bus.AddWatch(func(msg *gst.Message) bool {
 		switch msg.Type() {
+		case gst.MessageElement:
+			v.handleElementMessage(msg)
+		case gst.MessageTOC:
+			v.handleTOCMessage(msg)
 		case gst.MessageTag:
-			tags := msg.ParseTags()
-			title, ok := tags.GetString(gst.TagTitle)
-			if v.onTitle != nil && ok {
-				v.onTitle(title)
-			}
+			v.handleTagMessage(msg)
 		}
 		return true
 	})
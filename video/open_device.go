@@ -0,0 +1,148 @@
+package video
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// deviceCapsElementName is the name of the capsfilter that selects the format of the devices.
+const deviceCapsElementName = "fyne-devicecaps"
+
+// listVideoDevices and createDeviceElement find the devices and create their source element,
+// they are replaced by the tests that run without camera.
+var (
+	listVideoDevices    = streamer.ListVideoDevices
+	createDeviceElement = streamer.Device.CreateElement
+)
+
+// DeviceURI returns the location that opens the capture device with the given size and frame
+// rate, e.g. "device:///dev/video0?width=1280&height=720&framerate=30000%2F1001". The frame rate
+// is one of the Formats of the device, so that it matches the caps of the device exactly. The
+// size and the frame rate can be 0 to let the device choose. The devices without path are found
+// by their name.
+func DeviceURI(device streamer.Device, width, height int, frameRate streamer.FrameRate) (fyne.URI, error) {
+	id := device.Path
+	if id == "" {
+		id = device.Name
+	}
+	location := "device://" + (&url.URL{Path: "/" + strings.TrimPrefix(id, "/")}).EscapedPath()
+
+	query := url.Values{}
+	if width > 0 && height > 0 {
+		query.Set("width", strconv.Itoa(width))
+		query.Set("height", strconv.Itoa(height))
+	}
+	if frameRate.Num > 0 && frameRate.Den > 0 {
+		query.Set("framerate", frameRate.String())
+	}
+	if len(query) > 0 {
+		location += "?" + query.Encode()
+	}
+	return storage.ParseURI(location)
+}
+
+// devicePipeline is the pipeline of the capture devices, e.g. "device:///dev/video0". The source
+// element is created by the device, and linked to the capsfilter by setupDevice. It has this
+// structure:
+//
+//	+--------------+
+//	|   source     |
+//	| (the device) |
+//	+------+-------+
+//	       ↓
+//	+--------------+
+//	|  capsfilter  |
+//	+------+-------+
+//	       ↓
+//	+--------------+
+//	|  decodebin   |
+//	+------+-------+
+//	       ↓
+//	    the video branch of urlPipeline
+//
+// The decodebin decodes the cameras that send JPEG images, the raw frames go through it.
+func devicePipeline(location fyne.URI, options *OpenOptions) (string, error) {
+	if _, err := findDevice(location); err != nil {
+		return "", err
+	}
+	return `
+    # the source element of the device is linked to the capsfilter by the viewer
+    capsfilter name=` + deviceCapsElementName + ` !
+    decodebin name={{ .DecodeElementName }}
+
+    # manage the video
+    {{ .DecodeElementName }}. !
+    queue !
+    videoconvert !
+    videoscale !
+    videorate name={{ .VideoRateElementName }} !
+    videobalance name={{ .VideoBalanceElementName }} !
+    video/x-raw,format=RGBA !
+    appsink name={{ .AppSinkElementName }} sync=true
+    `, nil
+}
+
+// setupDevice creates the source element of the device, and selects the format of the location.
+// The device is live, so it can't be seeked and has no duration.
+func (v *Viewer) setupDevice(location fyne.URI, options *OpenOptions) error {
+	device, err := findDevice(location)
+	if err != nil {
+		return err
+	}
+	source, err := createDeviceElement(device, streamer.InputElementName)
+	if err != nil {
+		return err
+	}
+	if err := v.pipeline.Add(source); err != nil {
+		return err
+	}
+	filter, err := v.pipeline.GetElementByName(deviceCapsElementName)
+	if err != nil {
+		return err
+	}
+	filter.SetArg("caps", deviceCaps(location))
+	if err := source.Link(filter); err != nil {
+		return err
+	}
+
+	v.setLive(true)
+	return nil
+}
+
+// findDevice returns the video capture device of the location, found by its path or its name.
+func findDevice(location fyne.URI) (streamer.Device, error) {
+	id, err := url.PathUnescape(location.Path())
+	if err != nil {
+		return streamer.Device{}, err
+	}
+	id = strings.TrimPrefix(id, "/")
+	for _, device := range listVideoDevices() {
+		if (device.Path != "" && strings.TrimPrefix(device.Path, "/") == id) || (device.Path == "" && device.Name == id) {
+			return device, nil
+		}
+	}
+	return streamer.Device{}, fmt.Errorf("%w: %s", streamer.ErrDeviceNotFound, id)
+}
+
+// deviceCaps returns the caps of the size and the frame rate of the location, for the raw and the
+// JPEG formats of the devices. The frame rate is kept as the fraction of the location.
+func deviceCaps(location fyne.URI) string {
+	query, _ := url.ParseQuery(location.Query())
+	fields := ""
+	width, _ := strconv.Atoi(query.Get("width"))
+	height, _ := strconv.Atoi(query.Get("height"))
+	if width > 0 && height > 0 {
+		fields += fmt.Sprintf(",width=%d,height=%d", width, height)
+	}
+	var rate streamer.FrameRate
+	if _, err := fmt.Sscanf(query.Get("framerate"), "%d/%d", &rate.Num, &rate.Den); err == nil && rate.Num > 0 && rate.Den > 0 {
+		fields += ",framerate=" + rate.String()
+	}
+	return "video/x-raw" + fields + ";image/jpeg" + fields
+}
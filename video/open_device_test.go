@@ -0,0 +1,80 @@
+package video
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceURI(t *testing.T) {
+	u, err := DeviceURI(streamer.Device{Name: "Camera", Path: "/dev/video0"}, 1280, 720, streamer.FrameRate{Num: 30000, Den: 1001})
+	assert.Nil(t, err)
+	assert.Equal(t, "device:///dev/video0?framerate=30000%2F1001&height=720&width=1280", u.String())
+	assert.Equal(t, "video/x-raw,width=1280,height=720,framerate=30000/1001;image/jpeg,width=1280,height=720,framerate=30000/1001", deviceCaps(u))
+
+	u, err = DeviceURI(streamer.Device{Name: "Integrated Camera"}, 0, 0, streamer.FrameRate{})
+	assert.Nil(t, err)
+	assert.Equal(t, "device:///Integrated%20Camera", u.String())
+	assert.Equal(t, "video/x-raw;image/jpeg", deviceCaps(u))
+}
+
+// useTestDevice replaces the capture devices by a live videotestsrc, until the end of the test.
+func useTestDevice(t *testing.T) streamer.Device {
+	device := streamer.Device{
+		Name:  "Test Camera",
+		Path:  "/dev/test-camera",
+		Class: streamer.VideoSourceClass,
+		Formats: []streamer.DeviceFormat{{
+			MediaType:  "video/x-raw",
+			Width:      320,
+			Height:     240,
+			FrameRates: []streamer.FrameRate{{Num: 30000, Den: 1001}},
+		}},
+	}
+	listVideoDevices = func() []streamer.Device { return []streamer.Device{device} }
+	createDeviceElement = func(d streamer.Device, name string) (*gst.Element, error) {
+		return gst.NewElementWithProperties("videotestsrc", map[string]interface{}{"name": name, "is-live": true})
+	}
+	t.Cleanup(func() {
+		listVideoDevices = streamer.ListVideoDevices
+		createDeviceElement = streamer.Device.CreateElement
+	})
+	return device
+}
+
+func TestOpenDevice(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	device := useTestDevice(t)
+
+	u, _ := storage.ParseURI("device:///dev/no-such-camera")
+	err := video.Open(u)
+	assert.True(t, errors.Is(err, streamer.ErrDeviceNotFound))
+
+	format := device.Formats[0]
+	u, err = DeviceURI(device, format.Width, format.Height, format.FrameRates[0])
+	assert.Nil(t, err)
+	err = video.Open(u)
+	assert.Nil(t, err)
+	assert.True(t, video.IsLive())
+
+	var frames atomic.Int32
+	video.SetOnNewFrame(func(time.Duration) {
+		frames.Add(1)
+	})
+	err = video.Play()
+	assert.Nil(t, err)
+	time.Sleep(2 * time.Second)
+	assert.True(t, frames.Load() > 0)
+	// the exact rate of the device is negotiated
+	assert.InDelta(t, format.FrameRates[0].Float64(), video.FrameRate(), 0.001)
+	video.Stop()
+}
@@ -5,22 +5,100 @@ import (
 	"time"
 
 	"fyne.io/fyne/v2"
+	"github.com/metal3d/fyne-streamer/internal/adaptive"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+	"github.com/metal3d/fyne-streamer/playlist"
 )
 
-// Open opens the given location. It can be a file URI, an http or https URL.
+// Open opens the given location. It can be a file URI, an http or https URL. The HLS (".m3u8")
+// and DASH (".mpd") manifests are played as adaptive streams, see Variants. The rtsp and rtsps
+// locations, the MPEG-TS in UDP packets ("udp://host:port") and the RTP streams
+// ("rtp://host:port") are opened as live streams, see OpenWithOptions to configure them. The
+// other locations are read from their fyne storage repository, see OpenReader, unless an opener
+// is registered for their scheme with RegisterOpener.
+//
+// If the location is a playlist file (M3U, PLS or XSPF), its entries replace the ones of the
+// attached playlist, or of a new playlist that is attached to the viewer, and the first entry
+// is opened. See SetPlaylist.
 func (v *Viewer) Open(u fyne.URI) error {
-	switch u.Scheme() {
-	case "http", "https":
-		return v.openURL(u)
-	case "file":
-		return v.openFile(u)
-	default:
-		return fmt.Errorf("unsupported scheme %q", u.Scheme())
+	return v.OpenWithOptions(u, nil)
+}
+
+// OpenWithOptions opens the given location as Open does, with the settings of the network
+// sources. The options can be nil to use the defaults.
+func (v *Viewer) OpenWithOptions(u fyne.URI, options *OpenOptions) error {
+	if playlist.DetectFormat(u) != playlist.FormatUnknown {
+		return v.openPlaylist(u)
 	}
+	if options == nil {
+		options = &OpenOptions{}
+	}
+	return v.openMedia(u, options)
 }
 
-// OpenURL opens the given stream from http or https URL.
-// The pipeline has this structure:
+// openMedia opens the media at the given location with the opener of its scheme, or with the
+// reader of its fyne storage repository if no opener is registered for the scheme.
+func (v *Viewer) openMedia(u fyne.URI, options *OpenOptions) error {
+	opener, ok := lookupOpener(u.Scheme())
+	if !ok {
+		// the location is not kept, the hidden pipelines of the snapshots and the previews can't
+		// read it
+		return v.openStorage(u)
+	}
+
+	pipeline, err := opener.pipeline(u, options)
+	if err != nil {
+		return err
+	}
+	if err := v.SetPipelineFromString(pipeline); err != nil {
+		return err
+	}
+	if err := v.setMaxLateness(); err != nil {
+		return err
+	}
+	if opener.setup != nil {
+		if err := opener.setup(v, u, options); err != nil {
+			return err
+		}
+	}
+	v.uri = u
+	return nil
+}
+
+// openPlaylist loads the entries of the playlist file in the attached playlist, or in a new one,
+// and opens the first entry.
+func (v *Viewer) openPlaylist(u fyne.URI) error {
+	loaded, err := playlist.Load(u)
+	if err != nil {
+		return err
+	}
+	if v.playlist == nil {
+		v.SetPlaylist(playlist.New())
+	}
+	v.playlist.Clear()
+	v.playlist.AddEntries(loaded.Entries()...)
+	entry, err := v.playlist.Select(0)
+	if err != nil {
+		return err
+	}
+	return v.openMedia(entry.URI, &OpenOptions{})
+}
+
+// setupURL starts to download the manifest of the HLS and DASH streams in the background, to know
+// their variants, see Variants and SetVariant. The decodebin of the pipeline plugs the adaptive
+// demuxer (hlsdemux or dashdemux) that downloads the fragments of the variant that fits the
+// connection speed.
+func (v *Viewer) setupURL(location fyne.URI, options *OpenOptions) error {
+	if kind := adaptive.KindOf(location); kind != adaptive.None {
+		v.variantLock.Lock()
+		fetch := v.variantFetch
+		v.variantLock.Unlock()
+		go v.fetchVariants(location, kind, fetch)
+	}
+	return nil
+}
+
+// urlPipeline is the pipeline of the http and https locations. It has this structure:
 //
 //	        +--------------+
 //	        |  souphttpsrc  |
@@ -30,29 +108,46 @@ func (v *Viewer) Open(u fyne.URI) error {
 //	        |  decodebin   |
 //	        +------+-------+
 //	           ↓         ↓
-//	+--------------+   +--------------+
-//	| videoconvert |   | audioconvert |
-//	+------+-------+   +----+---------+
+//	+--------------+   +----------------+
+//	| videoconvert |   | input-selector |
+//	+------+-------+   +----+-----------+
 //	       ↓                ↓
 //	+--------------+  +---------------+
-//	|  videorate   |  | audioresample |
+//	|  videorate   |  | audioconvert  |
 //	+------+-------+  +-----+---------+
 //	       ↓                ↓
 //	+-------------+   +---------------+
-//	|  videoscale |   | autoaudiosink |
-//	+------+------+   +---------------+
-//	       ↓
-//	+-------------+
-//	|   jpegenc   |
-//	+------+------+
-//	       ↓
-//	+-------------+
-//	|   appsink   |
-//	+-------------+
-func (v *Viewer) openURL(location fyne.URI) error {
+//	|  videoscale |   |  scaletempo   |
+//	+------+------+   +-----+---------+
+//	       ↓                ↓
+//	+-------------+   +---------------+
+//	| RGBA caps   |   | audioresample |
+//	+------+------+   +-----+---------+
+//	       ↓                ↓
+//	+-------------+   +---------------+
+//	|   appsink   |   |   spectrum    |
+//	+-------------+   +-----+---------+
+//	                        ↓
+//	                  +---------------+
+//	                  |volume ! level |
+//	                  +-----+---------+
+//	                        ↓
+//	                  +---------------+
+//	                  | autoaudiosink |
+//	                  +---------------+
+//
+// Each audio track is linked to the input-selector, see SelectAudioTrack. The text subtitle
+// tracks are linked to another input-selector, that is followed by an appsink to display
+// them over the frame, see SelectSubtitleTrack.
+func urlPipeline(location fyne.URI, options *OpenOptions) (string, error) {
+	source, err := utils.SourceElement(location)
+	if err != nil {
+		return "", err
+	}
+
 	pipeline := `
     # the video source is sent to decoder
-    souphttpsrc name={{ .InputElementName }} location=%[1]q ! 
+    %[1]s name={{ .InputElementName }} ! 
     decodebin name={{ .DecodeElementName }} use-buffering=true
 
     # manage the video
@@ -62,27 +157,29 @@ func (v *Viewer) openURL(location fyne.URI) error {
     videoscale !
     videorate name={{ .VideoRateElementName }} ! 
     videobalance name={{ .VideoBalanceElementName }} !
-    jpegenc name={{ .ImageEncoderElementName }} ! 
-    appsink name={{ .AppSinkElementName }} sync=true max-lateness=%[2]d
+    video/x-raw,format=RGBA !
+    appsink name={{ .AppSinkElementName }} sync=true
 
-    # manage the sound
-    {{ .DecodeElementName }}. ! 
+    # manage the sound, the audio tracks are linked to the selector by the viewer
+    input-selector name={{ .AudioSelectorElementName }} !
     queue !
     audioconvert ! 
+    scaletempo !
+    audioconvert !
     audioresample ! 
-    volume name={{ .VolumeElementName }}  !
+    spectrum name={{ .SpectrumElementName }} post-messages=false !
+    volume name={{ .VolumeElementName }} !
+    level name={{ .LevelElementName }} post-messages=false !
     autoaudiosink sync=true
-    `
 
-	pipeline = fmt.Sprintf(
-		pipeline,
-		location.String(),
-		int64(time.Second.Nanoseconds()/int64(v.rate)),
-	)
-	return v.SetPipelineFromString(pipeline)
+    # the text subtitles are also linked to their selector by the viewer
+    input-selector name={{ .SubtitleSelectorElementName }} !
+    appsink name={{ .SubtitleSinkElementName }} sync=true async=false
+    `
+	return fmt.Sprintf(pipeline, source), nil
 }
 
-// Open a video from file. The pipeline has this structure:
+// filePipeline is the pipeline of the file locations. The pipeline has this structure:
 //
 //	        +--------------+
 //	        |   filsesrc   |
@@ -94,30 +191,55 @@ func (v *Viewer) openURL(location fyne.URI) error {
 //	               ↓
 //	         +-----+-----+
 //	         ↓           ↓
-//	+--------------+   +--------------+
-//	| videoconvert |   | audioconvert |
-//	+------+-------+   +----+---------+
+//	+--------------+   +----------------+
+//	| videoconvert |   | input-selector |
+//	+------+-------+   +----+-----------+
 //	       ↓                ↓
 //	+--------------+  +---------------+
-//	|  videorate   |  | audioresample |
+//	|  videorate   |  | audioconvert  |
 //	+------+-------+  +-----+---------+
 //	       ↓                ↓
 //	+-------------+   +---------------+
-//	|   jpegenc   |   | autoaudiosink |
-//	+------+------+   +---------------+
-//	       ↓
-//	+-------------+
-//	|   appsink   |
-//	+-------------+
+//	|  RGBA caps  |   |  scaletempo   |
+//	+------+------+   +-----+---------+
+//	       ↓                ↓
+//	+-------------+   +---------------+
+//	|   appsink   |   | audioresample |
+//	+-------------+   +-----+---------+
+//	                        ↓
+//	                  +---------------+
+//	                  |   spectrum    |
+//	                  +-----+---------+
+//	                        ↓
+//	                  +---------------+
+//	                  |volume ! level |
+//	                  +-----+---------+
+//	                        ↓
+//	                  +---------------+
+//	                  | autoaudiosink |
+//	                  +---------------+
 //
-// The appsink element provides the frames, and the audio is
+// The input-selector element receives all the audio tracks, see SelectAudioTrack. The text
+// subtitle tracks go to another input-selector and an appsink, see SelectSubtitleTrack.
+// The scaletempo element keeps the pitch of the audio when the playback rate changes, the
+// spectrum element posts the frequency bands used by SetOnSpectrum and the level element
+// posts the levels used by SetOnAudioLevel.
+// The appsink element provides raw RGBA frames, and the audio is
 // connected to the default audio output of the system.
-func (v *Viewer) openFile(location fyne.URI) error {
-	v.reset()
+func filePipeline(location fyne.URI, options *OpenOptions) (string, error) {
+	source, err := utils.SourceElement(location)
+	if err != nil {
+		return "", err
+	}
+	return sourcePipeline(source), nil
+}
 
+// sourcePipeline returns the pipeline of filePipeline with the given description of the source
+// element.
+func sourcePipeline(source string) string {
 	pipeline := `
     # the video source is sent to decoder
-    filesrc name={{ .InputElementName }} location=%[1]q !
+    %[1]s name={{ .InputElementName }} !
     decodebin name={{ .DecodeElementName }} use-buffering=true
 
     # manage the video
@@ -127,21 +249,24 @@ func (v *Viewer) openFile(location fyne.URI) error {
     videorate name={{ .VideoRateElementName }} !
     videoscale !
     videobalance name={{ .VideoBalanceElementName }} !
-    jpegenc name={{ .ImageEncoderElementName }} !
-    appsink name={{ .AppSinkElementName }} sync=true max-lateness=%[2]d
+    video/x-raw,format=RGBA !
+    appsink name={{ .AppSinkElementName }} sync=true
 
-    # manage the sound
-    {{ .DecodeElementName }}. !
+    # manage the sound, the audio tracks are linked to the selector by the viewer
+    input-selector name={{ .AudioSelectorElementName }} !
     queue max-size-buffers=0 max-size-time=%[2]d !
     audioconvert !
+    scaletempo !
+    audioconvert !
     audioresample !
-    volume name={{ .VolumeElementName }}  !
+    spectrum name={{ .SpectrumElementName }} post-messages=false !
+    volume name={{ .VolumeElementName }} !
+    level name={{ .LevelElementName }} post-messages=false !
     autoaudiosink sync=true
+
+    # the text subtitles are also linked to their selector by the viewer
+    input-selector name={{ .SubtitleSelectorElementName }} !
+    appsink name={{ .SubtitleSinkElementName }} sync=true async=false
     `
-	pipeline = fmt.Sprintf(
-		pipeline,
-		location.Path(),
-		int64(time.Second.Nanoseconds()/int64(v.rate)),
-	)
-	return v.SetPipelineFromString(pipeline)
+	return fmt.Sprintf(pipeline, source, int64(time.Second/defaultMaxRate))
 }
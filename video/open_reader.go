@@ -0,0 +1,196 @@
+package video
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"github.com/go-gst/go-gst/gst"
+	"github.com/go-gst/go-gst/gst/app"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// readerChunkSize is the maximum size of the buffers read from the readers.
+const readerChunkSize = 64 * 1024
+
+// readerCaps are the caps of the containers that the hints of OpenReader name, so that the
+// decodebin doesn't have to find the type of the data.
+var readerCaps = []struct {
+	extensions []string
+	caps       string
+}{
+	{[]string{".webm"}, "video/webm"},
+	{[]string{".mkv", ".mka"}, "video/x-matroska"},
+	{[]string{".mp4", ".m4v", ".m4a", ".mov"}, "video/quicktime"},
+	{[]string{".ogg", ".ogv", ".oga"}, "application/ogg"},
+	{[]string{".ts", ".m2ts"}, "video/mpegts,systemstream=true"},
+	{[]string{".avi"}, "video/x-msvideo"},
+}
+
+// OpenReader opens the media read from r, e.g. a file embedded with embed.FS or a bytes.Reader.
+// The hint is the name or the extension of the media, e.g. "clip.webm" or ".webm", that gives
+// the container of the known formats. It can be empty to detect the type from the data.
+//
+// The data is sent to the pipeline with an appsrc element, that seeks the reader when the
+// viewer seeks. The reader must be usable until another media is opened, the viewer doesn't
+// close it.
+func (v *Viewer) OpenReader(r io.ReadSeeker, hint string) error {
+	if err := v.SetPipelineFromString(sourcePipeline("appsrc")); err != nil {
+		return err
+	}
+	if err := v.setMaxLateness(); err != nil {
+		return err
+	}
+	element, err := v.pipeline.GetElementByName(streamer.InputElementName)
+	if err != nil {
+		return err
+	}
+	source := app.SrcFromElement(element)
+	source.SetStreamType(app.AppStreamTypeSeekable)
+	source.SetSize(readerSize(r))
+	if caps := hintCaps(hint); caps != "" {
+		source.SetCaps(gst.NewCapsFromString(caps))
+	}
+
+	reader := &readerSource{reader: r}
+	source.SetCallbacks(&app.SourceCallbacks{
+		NeedDataFunc: reader.needData,
+		SeekDataFunc: reader.seekData,
+	})
+	return nil
+}
+
+// openStorage opens the location with the reader of its fyne storage repository. If the reader
+// can't seek, it is reopened to seek backward. The reader is closed when another media is opened.
+func (v *Viewer) openStorage(location fyne.URI) error {
+	reader, err := storage.Reader(location)
+	if err != nil {
+		return err
+	}
+	var seeker io.ReadSeeker
+	var closer io.Closer = reader
+	if s, ok := reader.(io.ReadSeeker); ok {
+		seeker = s
+	} else {
+		reopen := &reopenReader{uri: location, reader: reader}
+		seeker, closer = reopen, reopen
+	}
+	if err := v.OpenReader(seeker, location.Name()); err != nil {
+		closer.Close()
+		return err
+	}
+	v.reader = closer
+	return nil
+}
+
+// readerSize returns the size of the data of r, or -1 if it is unknown. The reader is rewound.
+func readerSize(r io.ReadSeeker) int64 {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		size = -1
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		fyne.LogError("Failed to rewind the reader", err)
+	}
+	return size
+}
+
+// hintCaps returns the caps of the container named by the hint of OpenReader, empty if the
+// container is unknown.
+func hintCaps(hint string) string {
+	ext := strings.ToLower(filepath.Ext(hint))
+	if ext == "" && hint != "" {
+		ext = "." + strings.ToLower(hint)
+	}
+	for _, known := range readerCaps {
+		for _, extension := range known.extensions {
+			if extension == ext {
+				return known.caps
+			}
+		}
+	}
+	return ""
+}
+
+// readerSource feeds an appsrc element with the data of a reader.
+type readerSource struct {
+	lock   sync.Mutex // protects the reader that is read and seeked by the streaming threads
+	reader io.ReadSeeker
+}
+
+// needData pushes the next buffer of the reader, or ends the stream at the end of the data.
+func (s *readerSource) needData(source *app.Source, length uint) {
+	if length == 0 || length > readerChunkSize {
+		length = readerChunkSize
+	}
+	data := make([]byte, length)
+	s.lock.Lock()
+	n, err := io.ReadFull(s.reader, data)
+	s.lock.Unlock()
+
+	if n > 0 {
+		source.PushBuffer(gst.NewBufferFromBytes(data[:n]))
+	}
+	if err != nil {
+		if err != io.EOF && err != io.ErrUnexpectedEOF {
+			fyne.LogError("Failed to read the media", err)
+		}
+		source.EndStream()
+	}
+}
+
+// seekData moves the reader to the offset, in bytes, asked by the appsrc element.
+func (s *readerSource) seekData(source *app.Source, offset uint64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, err := s.reader.Seek(int64(offset), io.SeekStart); err != nil {
+		fyne.LogError("Failed to seek the media", err)
+		return false
+	}
+	return true
+}
+
+// errUnknownSize is returned when a reopenReader is seeked from its end.
+var errUnknownSize = fmt.Errorf("the size of the data is unknown")
+
+// reopenReader makes the reader of a fyne storage location seekable: it is reopened to seek
+// backward, and the data is skipped to seek forward.
+type reopenReader struct {
+	uri    fyne.URI
+	reader fyne.URIReadCloser
+	offset int64
+}
+
+func (r *reopenReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *reopenReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		return r.offset, errUnknownSize
+	}
+	if offset < r.offset {
+		reader, err := storage.Reader(r.uri)
+		if err != nil {
+			return r.offset, err
+		}
+		r.reader.Close()
+		r.reader, r.offset = reader, 0
+	}
+	n, err := io.CopyN(io.Discard, r.reader, offset-r.offset)
+	r.offset += n
+	return r.offset, err
+}
+
+func (r *reopenReader) Close() error {
+	return r.reader.Close()
+}
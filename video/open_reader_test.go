@@ -0,0 +1,148 @@
+package video
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/storage/repository"
+	"fyne.io/fyne/v2/test"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryRepository serves the test video for every location of the "mem" scheme, with a reader
+// that can't seek.
+type memoryRepository struct {
+	data []byte
+}
+
+type memoryReader struct {
+	io.Reader
+	uri fyne.URI
+}
+
+func (r *memoryReader) Close() error  { return nil }
+func (r *memoryReader) URI() fyne.URI { return r.uri }
+
+func (m *memoryRepository) Exists(u fyne.URI) (bool, error)  { return true, nil }
+func (m *memoryRepository) CanRead(u fyne.URI) (bool, error) { return true, nil }
+func (m *memoryRepository) Destroy(string)                   {}
+func (m *memoryRepository) Reader(u fyne.URI) (fyne.URIReadCloser, error) {
+	return &memoryReader{Reader: bytes.NewReader(m.data), uri: u}, nil
+}
+
+func TestHintCaps(t *testing.T) {
+	assert.Equal(t, "video/webm", hintCaps("clip.webm"))
+	assert.Equal(t, "application/ogg", hintCaps(".OGV"))
+	assert.Equal(t, "video/quicktime", hintCaps("mp4"))
+	assert.Equal(t, "", hintCaps("clip.xyz"))
+	assert.Equal(t, "", hintCaps(""))
+}
+
+func TestOpenReader(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+
+	file, err := os.Open(_testVideoFile)
+	assert.Nil(t, err)
+	defer file.Close()
+	err = video.OpenReader(file, "")
+	assert.Nil(t, err)
+
+	err = video.Play()
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	err = video.Seek(2 * time.Second)
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	pos, err := video.CurrentPosition()
+	assert.Nil(t, err)
+	assert.True(t, pos >= 2*time.Second)
+	video.Stop()
+}
+
+func TestOpenStorage(t *testing.T) {
+	setup(t)
+	test.NewApp()
+	data, err := os.ReadFile(_testVideoFile)
+	assert.Nil(t, err)
+	repository.Register("mem", &memoryRepository{data: data})
+
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	u, err := storage.ParseURI("mem://videos/testvideo.ogv")
+	assert.Nil(t, err)
+	err = video.Open(u)
+	assert.Nil(t, err)
+	assert.Nil(t, video.uri)
+
+	err = video.Play()
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	err = video.Seek(time.Second)
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	pos, err := video.CurrentPosition()
+	assert.Nil(t, err)
+	assert.True(t, pos >= time.Second)
+	video.Stop()
+
+	// the reader is reopened to seek backward
+	reader := &reopenReader{uri: u, reader: &memoryReader{Reader: bytes.NewReader(data), uri: u}}
+	offset, err := reader.Seek(100, io.SeekStart)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), offset)
+	offset, err = reader.Seek(-50, io.SeekCurrent)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(50), offset)
+	buf := make([]byte, 10)
+	_, err = io.ReadFull(reader, buf)
+	assert.Nil(t, err)
+	assert.Equal(t, data[50:60], buf)
+	_, err = reader.Seek(0, io.SeekEnd)
+	assert.NotNil(t, err)
+}
+
+func TestStorageSnapshotAndPreview(t *testing.T) {
+	setup(t)
+	data, err := os.ReadFile(_testVideoFile)
+	assert.Nil(t, err)
+	repository.Register("mem", &memoryRepository{data: data})
+
+	player := NewPlayer()
+	window := test.NewWindow(player)
+	window.Resize(fyne.NewSize(800, 600))
+	window.Show()
+
+	u, err := storage.ParseURI("mem://videos/testvideo.ogv")
+	assert.Nil(t, err)
+	err = player.Open(u)
+	assert.Nil(t, err)
+	player.Pause()
+	time.Sleep(500 * time.Millisecond)
+
+	// the hidden pipelines can't read the storage locations
+	_, err = player.SnapshotAt(time.Second)
+	assert.True(t, errors.Is(err, streamer.ErrNoLocation))
+
+	// the preview only shows the time
+	renderer := player.controls.renderer
+	cursorSize := renderer.cursor.Size()
+	renderer.cursor.MouseIn(&desktop.MouseEvent{
+		PointEvent: fyne.PointEvent{Position: fyne.NewPos(cursorSize.Width/2, cursorSize.Height/2)},
+	})
+	assert.True(t, renderer.preview.Visible())
+	assert.NotEmpty(t, renderer.previewTime.Text)
+	assert.False(t, renderer.previewImage.Visible())
+	renderer.cursor.MouseOut()
+	assert.NotContains(t, logBuffer.String(), "preview")
+	player.Stop()
+}
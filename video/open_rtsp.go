@@ -0,0 +1,64 @@
+package video
+
+import (
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// rtspPipeline is the pipeline of the live streams of the RTSP servers, e.g. IP cameras. It has
+// this structure:
+//
+//	        +--------------+
+//	        |   rtspsrc    |
+//	        +------+-------+
+//	           ↓         ↓
+//	+--------------+   +----------------+
+//	|  decodebin   |   |   decodebin    |
+//	| (the video)  |   | (other streams)|
+//	+------+-------+   +----+-----------+
+//	       ↓                ↓
+//	    the video and audio branches of liveBranches
+//
+// Unlike urlPipeline, liveBranches has no scaletempo element and no subtitle overlay: the playback
+// rate can't be changed and the embedded subtitles are not displayed on the live sources.
+//
+// The rtspsrc element has a pad for each stream of the server: the first video stream goes to
+// the decodebin of the pipeline, the others get their own decodebin, that links its audio
+// tracks to the audio selector, see setupRTSP.
+func rtspPipeline(location fyne.URI, options *OpenOptions) (string, error) {
+	return `
+    # the streams of the source are linked by the viewer
+    rtspsrc name={{ .InputElementName }}
+    decodebin name={{ .DecodeElementName }}
+    ` + liveBranches, nil
+}
+
+// setupRTSP sets the location and the options of the rtspsrc element, and links its streams.
+// The stream is live, so it can't be seeked and has no duration.
+func (v *Viewer) setupRTSP(location fyne.URI, options *OpenOptions) error {
+	source, err := v.pipeline.GetElementByName(streamer.InputElementName)
+	if err != nil {
+		return err
+	}
+	// the properties are not in the pipeline description, that would remove the "#" of the
+	// locations and passwords as comments
+	source.SetArg("location", location.String())
+	if options.Latency > 0 {
+		source.SetArg("latency", strconv.FormatInt(options.Latency.Milliseconds(), 10))
+	}
+	switch options.Transport {
+	case TransportUDP:
+		source.SetArg("protocols", "udp")
+	case TransportTCP:
+		source.SetArg("protocols", "tcp")
+	}
+	if options.User != "" {
+		source.SetArg("user-id", options.User)
+		source.SetArg("user-pw", options.Password)
+	}
+
+	v.setLive(true)
+	return v.linkLiveSource(source)
+}
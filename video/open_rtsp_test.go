@@ -0,0 +1,82 @@
+package video
+
+import (
+	"errors"
+	"os/exec"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenRTSP(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+
+	u, err := storage.ParseURI("rtsp://127.0.0.1:8554/camera")
+	assert.Nil(t, err)
+	err = video.OpenWithOptions(u, &OpenOptions{
+		Latency:   200 * time.Millisecond,
+		Transport: TransportTCP,
+		User:      "admin",
+		Password:  "se#cret",
+	})
+	assert.Nil(t, err)
+	assert.True(t, video.IsLive())
+
+	source, err := video.pipeline.GetElementByName(streamer.InputElementName)
+	assert.Nil(t, err)
+	location, _ := source.GetProperty("location")
+	assert.Equal(t, "rtsp://127.0.0.1:8554/camera", location)
+	latency, _ := source.GetProperty("latency")
+	assert.Equal(t, uint(200), latency)
+	password, _ := source.GetProperty("user-pw")
+	assert.Equal(t, "se#cret", password)
+
+	_, err = video.Duration()
+	assert.True(t, errors.Is(err, streamer.ErrNoDuration))
+	err = video.Seek(time.Second)
+	assert.True(t, errors.Is(err, streamer.ErrSeekUnsupported))
+
+	// the file is not live
+	err = video.Open(storage.NewFileURI(_testVideoFile))
+	assert.Nil(t, err)
+	assert.False(t, video.IsLive())
+}
+
+func TestPlayRTSP(t *testing.T) {
+	// test-launch is the example server of gst-rtsp-server
+	server, err := exec.LookPath("test-launch")
+	if err != nil {
+		t.Skip("test-launch of gst-rtsp-server is needed to serve a RTSP stream")
+	}
+	setup(t)
+	cmd := exec.Command(server, "--port", "18554",
+		"( videotestsrc is-live=true ! video/x-raw,width=320,height=240 ! x264enc tune=zerolatency ! rtph264pay name=pay0 pt=96 )")
+	assert.Nil(t, cmd.Start())
+	defer cmd.Process.Kill()
+	time.Sleep(500 * time.Millisecond)
+
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	u, _ := storage.ParseURI("rtsp://127.0.0.1:18554/test")
+	err = video.OpenWithOptions(u, &OpenOptions{Latency: 100 * time.Millisecond, Transport: TransportTCP})
+	assert.Nil(t, err)
+
+	var frames atomic.Int32
+	video.SetOnNewFrame(func(time.Duration) {
+		frames.Add(1)
+	})
+	err = video.Play()
+	assert.Nil(t, err)
+	time.Sleep(3 * time.Second)
+	assert.True(t, frames.Load() > 0)
+	assert.Equal(t, fyne.NewSize(320, 240), video.VideoSize())
+	video.Stop()
+}
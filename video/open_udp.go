@@ -0,0 +1,205 @@
+package video
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+// defaultRTPLatency is the size of the jitter buffer of the rtp locations if the options don't
+// give one.
+const defaultRTPLatency = 200 * time.Millisecond
+
+// tsDemuxElementName is the name of the MPEG-TS demuxer of the udp locations.
+const tsDemuxElementName = "fyne-tsdemux"
+
+// rtpEncodings are the encodings that the rtp locations receive, with their depayloader.
+var rtpEncodings = []struct {
+	name        string
+	depayloader string
+}{
+	{"H264", "rtph264depay"},
+	{"H265", "rtph265depay"},
+	{"VP8", "rtpvp8depay"},
+	{"VP9", "rtpvp9depay"},
+	{"JPEG", "rtpjpegdepay"},
+	{"MP2T", "rtpmp2tdepay"},
+}
+
+// udpPipeline is the pipeline of the MPEG-TS streams received in UDP packets, e.g.
+// "udp://239.0.0.1:1234" for a multicast group, or "udp://0.0.0.0:1234" to listen on every
+// interface. It has this structure:
+//
+//	        +--------------+
+//	        |    udpsrc    |
+//	        +------+-------+
+//	               ↓
+//	        +--------------+
+//	        |   tsdemux    |
+//	        +------+-------+
+//	           ↓         ↓
+//	+--------------+   +----------------+
+//	|  decodebin   |   |   decodebin    |
+//	| (the video)  |   | (other streams)|
+//	+------+-------+   +----+-----------+
+//	       ↓                ↓
+//	    the video and audio branches of liveBranches
+//
+// The streams of the demuxer are linked as the ones of the RTSP sources, see setupUDP. As with
+// all the live sources, the playback rate can't be changed and the embedded subtitles are not
+// displayed.
+func udpPipeline(location fyne.URI, options *OpenOptions) (string, error) {
+	return `
+    udpsrc name={{ .InputElementName }} !
+    queue !
+    tsdemux name=` + tsDemuxElementName + `
+
+    # the streams of the demuxer are linked by the viewer
+    decodebin name={{ .DecodeElementName }}
+    ` + liveBranches, nil
+}
+
+// setupUDP sets the address of the udpsrc element, and links the streams of the demuxer.
+func (v *Viewer) setupUDP(location fyne.URI, options *OpenOptions) error {
+	caps := options.Caps
+	if caps == "" {
+		caps = "video/mpegts,systemstream=true,packetsize=188"
+	}
+	if err := v.setUDPSource(location, options, caps); err != nil {
+		return err
+	}
+	demux, err := v.pipeline.GetElementByName(tsDemuxElementName)
+	if err != nil {
+		return err
+	}
+
+	v.setLive(true)
+	return v.linkLiveSource(demux)
+}
+
+// rtpPipeline is the pipeline of the RTP streams received in UDP packets, e.g.
+// "rtp://239.0.0.1:5004". The encoding of the packets is given by the EncodingName or the Caps
+// of the options, H264 by default. It has this structure:
+//
+//	+--------------+
+//	|    udpsrc    |
+//	+------+-------+
+//	       ↓
+//	+-----------------+
+//	| rtpjitterbuffer |
+//	+------+----------+
+//	       ↓
+//	+-----------------+
+//	|  depayloader    |
+//	+------+----------+
+//	       ↓
+//	+--------------+
+//	|  decodebin   |
+//	+------+-------+
+//	       ↓
+//	    the video and audio branches of liveBranches
+//
+// The playback rate can't be changed on this live source.
+func rtpPipeline(location fyne.URI, options *OpenOptions) (string, error) {
+	_, encoding := rtpCaps(options)
+	depayloader := ""
+	for _, known := range rtpEncodings {
+		if known.name == encoding {
+			depayloader = known.depayloader
+		}
+	}
+	if depayloader == "" {
+		return "", fmt.Errorf("%w: %q", streamer.ErrUnsupportedEncoding, encoding)
+	}
+
+	latency := options.Latency
+	if latency <= 0 {
+		latency = defaultRTPLatency
+	}
+	pipeline := `
+    udpsrc name={{ .InputElementName }} !
+    rtpjitterbuffer latency=%[1]d !
+    %[2]s !
+    decodebin name={{ .DecodeElementName }}
+    ` + liveBranches
+	return fmt.Sprintf(pipeline, latency.Milliseconds(), depayloader), nil
+}
+
+// setupRTP sets the address and the caps of the udpsrc element.
+func (v *Viewer) setupRTP(location fyne.URI, options *OpenOptions) error {
+	caps, _ := rtpCaps(options)
+	if err := v.setUDPSource(location, options, caps); err != nil {
+		return err
+	}
+	v.setLive(true)
+	return nil
+}
+
+// rtpCaps returns the caps of the RTP packets and their encoding, from the options.
+func rtpCaps(options *OpenOptions) (string, string) {
+	if options.Caps != "" {
+		return options.Caps, rtpCapsEncoding(options.Caps)
+	}
+	encoding := strings.ToUpper(options.EncodingName)
+	if encoding == "" {
+		encoding = "H264"
+	}
+	return fmt.Sprintf("application/x-rtp,media=video,clock-rate=90000,encoding-name=%s", encoding), encoding
+}
+
+// setUDPSource sets the address, the port and the caps of the udpsrc element of the pipeline.
+func (v *Viewer) setUDPSource(location fyne.URI, options *OpenOptions, caps string) error {
+	host, port, err := udpAddress(location)
+	if err != nil {
+		return err
+	}
+	source, err := v.pipeline.GetElementByName(streamer.InputElementName)
+	if err != nil {
+		return err
+	}
+	source.SetArg("address", host)
+	source.SetArg("port", strconv.Itoa(port))
+	source.SetArg("caps", caps)
+	if options.MulticastInterface != "" {
+		source.SetArg("multicast-iface", options.MulticastInterface)
+	}
+	return nil
+}
+
+// udpAddress returns the host and the port of an udp or rtp location.
+func udpAddress(location fyne.URI) (string, int, error) {
+	// the authority of the IPv6 addresses has no brackets, the port is after the last colon
+	authority := location.Authority()
+	i := strings.LastIndex(authority, ":")
+	if i < 0 {
+		return "", 0, fmt.Errorf("%w: no port in %q", streamer.ErrInvalidAddress, location)
+	}
+	port, err := strconv.Atoi(authority[i+1:])
+	if err != nil || port <= 0 || port > 65535 {
+		return "", 0, fmt.Errorf("%w: invalid port in %q", streamer.ErrInvalidAddress, location)
+	}
+	host := strings.Trim(authority[:i], "[]")
+	if host == "" {
+		host = "0.0.0.0"
+	}
+	return host, port, nil
+}
+
+// rtpCapsEncoding returns the "encoding-name" of the RTP caps, empty if the caps are invalid.
+func rtpCapsEncoding(caps string) string {
+	parsed := gst.NewCapsFromString(caps)
+	if parsed == nil || parsed.GetSize() == 0 {
+		return ""
+	}
+	value, err := parsed.GetStructureAt(0).GetValue("encoding-name")
+	if err != nil {
+		return ""
+	}
+	encoding, _ := value.(string)
+	return strings.ToUpper(encoding)
+}
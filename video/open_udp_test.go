@@ -0,0 +1,118 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+// startUDPSender sends a live H264 stream to the local port, muxed by the given element, e.g.
+// "mpegtsmux" or "rtph264pay".
+func startUDPSender(t *testing.T, muxer string, port int) *gst.Pipeline {
+	if gst.Find("x264enc") == nil {
+		t.Skip("x264enc is needed to send the stream")
+	}
+	sender, err := gst.NewPipelineFromString(fmt.Sprintf(`
+    videotestsrc is-live=true ! video/x-raw,width=320,height=240,framerate=30/1 !
+    x264enc tune=zerolatency key-int-max=30 ! h264parse config-interval=1 ! %s !
+    udpsink host=127.0.0.1 port=%d`, muxer, port))
+	assert.Nil(t, err)
+	assert.Nil(t, sender.SetState(gst.StatePlaying))
+	t.Cleanup(func() {
+		sender.SetState(gst.StateNull)
+	})
+	return sender
+}
+
+func TestUDPAddress(t *testing.T) {
+	u, _ := storage.ParseURI("udp://239.0.0.1:1234")
+	host, port, err := udpAddress(u)
+	assert.Nil(t, err)
+	assert.Equal(t, "239.0.0.1", host)
+	assert.Equal(t, 1234, port)
+
+	u, _ = storage.ParseURI("rtp://[ff02::1]:5004")
+	host, port, err = udpAddress(u)
+	assert.Nil(t, err)
+	assert.Equal(t, "ff02::1", host)
+	assert.Equal(t, 5004, port)
+
+	u, _ = storage.ParseURI("udp://239.0.0.1")
+	_, _, err = udpAddress(u)
+	assert.True(t, errors.Is(err, streamer.ErrInvalidAddress))
+}
+
+func TestOpenRTP(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+
+	u, _ := storage.ParseURI("rtp://239.0.0.1:5004")
+	err := video.OpenWithOptions(u, &OpenOptions{EncodingName: "vp8", MulticastInterface: "lo"})
+	assert.Nil(t, err)
+	assert.True(t, video.IsLive())
+
+	source, err := video.pipeline.GetElementByName(streamer.InputElementName)
+	assert.Nil(t, err)
+	address, _ := source.GetProperty("address")
+	assert.Equal(t, "239.0.0.1", address)
+	port, _ := source.GetProperty("port")
+	assert.Equal(t, 5004, port)
+	iface, _ := source.GetProperty("multicast-iface")
+	assert.Equal(t, "lo", iface)
+	depayloader := false
+	elements, _ := video.pipeline.GetElements()
+	for _, element := range elements {
+		depayloader = depayloader || element.GetFactory().GetName() == "rtpvp8depay"
+	}
+	assert.True(t, depayloader)
+
+	err = video.OpenWithOptions(u, &OpenOptions{EncodingName: "MP4V-ES"})
+	assert.True(t, errors.Is(err, streamer.ErrUnsupportedEncoding))
+	err = video.OpenWithOptions(u, &OpenOptions{
+		Caps: "application/x-rtp,media=video,clock-rate=90000,encoding-name=H265,payload=96",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "H265", rtpCapsEncoding("application/x-rtp,encoding-name=h265"))
+}
+
+func TestPlayUDP(t *testing.T) {
+	setup(t)
+	for _, tt := range []struct {
+		location string
+		muxer    string
+		port     int
+	}{
+		{"udp://127.0.0.1:15000", "mpegtsmux", 15000},
+		{"rtp://127.0.0.1:15004", "rtph264pay", 15004},
+	} {
+		t.Run(tt.muxer, func(t *testing.T) {
+			video := NewViewer()
+			_ = test.WidgetRenderer(video)
+			u, _ := storage.ParseURI(tt.location)
+			err := video.OpenWithOptions(u, &OpenOptions{Latency: 50 * time.Millisecond})
+			assert.Nil(t, err)
+
+			var frames atomic.Int32
+			video.SetOnNewFrame(func(time.Duration) {
+				frames.Add(1)
+			})
+			err = video.Play()
+			assert.Nil(t, err)
+			startUDPSender(t, tt.muxer, tt.port)
+			time.Sleep(3 * time.Second)
+			assert.True(t, frames.Load() > 0)
+			assert.Equal(t, fyne.NewSize(320, 240), video.VideoSize())
+			video.Stop()
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package video
+
+import (
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+)
+
+// Opener returns the pipeline of a location, see RegisterOpener. The pipeline is a template
+// rendered with the ElementMap, as the ones of SetPipelineFromString.
+type Opener func(location fyne.URI, options *OpenOptions) (pipelineTemplate string, err error)
+
+// opener is a registered Opener, with the setup that the viewer does after creating the pipeline
+// of the built-in schemes.
+type opener struct {
+	pipeline Opener
+	setup    func(v *Viewer, location fyne.URI, options *OpenOptions) error // nil if there is nothing to do
+}
+
+var (
+	openers     = map[string]opener{}
+	openersLock sync.RWMutex // protects the openers that can be registered by any goroutine
+)
+
+func init() {
+	registerBuiltinOpener(filePipeline, nil, "file")
+	registerBuiltinOpener(urlPipeline, (*Viewer).setupURL, "http", "https")
+	registerBuiltinOpener(rtspPipeline, (*Viewer).setupRTSP, "rtsp", "rtsps")
+	registerBuiltinOpener(udpPipeline, (*Viewer).setupUDP, "udp")
+	registerBuiltinOpener(rtpPipeline, (*Viewer).setupRTP, "rtp")
+	registerBuiltinOpener(devicePipeline, (*Viewer).setupDevice, "device")
+}
+
+// RegisterOpener makes Open and OpenWithOptions create the pipeline of the locations of the
+// scheme with fn, e.g. for "peertube" or "test" locations. The template that fn returns is
+// rendered with the ElementMap as the ones of SetPipelineFromString, so the appsink named
+// AppSinkElementName is mandatory, and the other named elements are managed by the viewer.
+//
+// It replaces the opener of the scheme, including the built-in ones ("file", "http", "https",
+// "rtsp", "rtsps", "udp", "rtp" and "device"). If fn is nil, the opener of the scheme is removed
+// and its locations are read from their fyne storage repository.
+func RegisterOpener(scheme string, fn func(fyne.URI, *OpenOptions) (pipelineTemplate string, err error)) {
+	openersLock.Lock()
+	defer openersLock.Unlock()
+	scheme = strings.ToLower(scheme)
+	if fn == nil {
+		delete(openers, scheme)
+		return
+	}
+	openers[scheme] = opener{pipeline: fn}
+}
+
+// registerBuiltinOpener registers the opener of built-in schemes, with the setup of their pipeline.
+func registerBuiltinOpener(
+	pipeline Opener,
+	setup func(v *Viewer, location fyne.URI, options *OpenOptions) error,
+	schemes ...string,
+) {
+	openersLock.Lock()
+	defer openersLock.Unlock()
+	for _, scheme := range schemes {
+		openers[scheme] = opener{pipeline: pipeline, setup: setup}
+	}
+}
+
+// lookupOpener returns the opener of the scheme, false if there is none.
+func lookupOpener(scheme string) (opener, bool) {
+	openersLock.RLock()
+	defer openersLock.RUnlock()
+	o, ok := openers[strings.ToLower(scheme)]
+	return o, ok
+}
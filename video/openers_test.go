@@ -0,0 +1,54 @@
+package video
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterOpener(t *testing.T) {
+	setup(t)
+	RegisterOpener("test", func(u fyne.URI, options *OpenOptions) (string, error) {
+		return fmt.Sprintf(`
+        videotestsrc name={{ .InputElementName }} pattern=%s num-buffers=30 !
+        video/x-raw,width=160,height=120 !
+        videoconvert !
+        videorate name={{ .VideoRateElementName }} !
+        video/x-raw,format=RGBA !
+        appsink name={{ .AppSinkElementName }}`, u.Authority()), nil
+	})
+	defer RegisterOpener("test", nil)
+
+	_, ok := lookupOpener("TEST")
+	assert.True(t, ok)
+	_, ok = lookupOpener("rtsp")
+	assert.True(t, ok)
+
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	u, _ := storage.ParseURI("test://ball")
+	err := video.Open(u)
+	assert.Nil(t, err)
+	assert.Equal(t, u, video.uri)
+
+	var frames atomic.Int32
+	video.SetOnNewFrame(func(time.Duration) {
+		frames.Add(1)
+	})
+	err = video.Play()
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	assert.True(t, frames.Load() > 0)
+	assert.Equal(t, fyne.NewSize(160, 120), video.VideoSize())
+	video.Stop()
+
+	RegisterOpener("test", nil)
+	_, ok = lookupOpener("test")
+	assert.False(t, ok)
+}
@@ -0,0 +1,194 @@
+package video
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var _ desktop.Hoverable = (*positionSlider)(nil)
+var _ fyne.Draggable = (*positionSlider)(nil)
+var _ fyne.WidgetRenderer = (*positionSliderRenderer)(nil)
+
+// positionSlider is the slider to navigate in the video. It reports the hovered or
+// dragged value to display a preview of the video at this position, and draws marks
+// on the track, e.g. at the start of the chapters.
+type positionSlider struct {
+	widget.Slider
+	onHover    func(value float64, x float32) // x is the pointer position in the slider
+	onHoverEnd func()
+	marks      []float64
+}
+
+// newPositionSlider creates a horizontal positionSlider.
+func newPositionSlider(min, max float64) *positionSlider {
+	s := &positionSlider{}
+	s.Min = min
+	s.Max = max
+	s.Step = 1
+	s.Orientation = widget.Horizontal
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+// CreateRenderer creates the renderer of the slider, with the marks.
+//
+// Implements: fyne.Widget
+func (s *positionSlider) CreateRenderer() fyne.WidgetRenderer {
+	r := &positionSliderRenderer{
+		WidgetRenderer: s.Slider.CreateRenderer(),
+		slider:         s,
+	}
+	r.Refresh()
+	return r
+}
+
+// DragEnd ends the preview.
+//
+// Implements: fyne.Draggable
+func (s *positionSlider) DragEnd() {
+	s.Slider.DragEnd()
+	s.hoverEnd()
+}
+
+// Dragged moves the slider and the preview.
+//
+// Implements: fyne.Draggable
+func (s *positionSlider) Dragged(e *fyne.DragEvent) {
+	s.Slider.Dragged(e)
+	s.hover(e.Position)
+}
+
+// MouseIn starts the preview.
+//
+// Implements: desktop.Hoverable
+func (s *positionSlider) MouseIn(e *desktop.MouseEvent) {
+	s.Slider.MouseIn(e)
+	s.hover(e.Position)
+}
+
+// MouseMoved moves the preview.
+//
+// Implements: desktop.Hoverable
+func (s *positionSlider) MouseMoved(e *desktop.MouseEvent) {
+	s.Slider.MouseMoved(e)
+	s.hover(e.Position)
+}
+
+// MouseOut ends the preview.
+//
+// Implements: desktop.Hoverable
+func (s *positionSlider) MouseOut() {
+	s.Slider.MouseOut()
+	s.hoverEnd()
+}
+
+// hover calls the onHover callback with the value under the pointer.
+func (s *positionSlider) hover(pos fyne.Position) {
+	if s.onHover == nil {
+		return
+	}
+	pad := s.endOffset()
+	width := s.Size().Width - pad*2
+	if width <= 0 {
+		return
+	}
+	ratio := float64((pos.X - pad) / width)
+	if ratio < 0 {
+		ratio = 0
+	} else if ratio > 1 {
+		ratio = 1
+	}
+	s.onHover(s.Min+ratio*(s.Max-s.Min), pos.X)
+}
+
+func (s *positionSlider) hoverEnd() {
+	if s.onHoverEnd == nil {
+		return
+	}
+	s.onHoverEnd()
+}
+
+// endOffset returns the space before and after the track, the same as the widget.Slider to
+// align with the thumb.
+func (s *positionSlider) endOffset() float32 {
+	return (theme.IconInlineSize()-4)/2 + theme.InnerPadding() - 1.5
+}
+
+// setMarks changes the values where a mark is drawn on the track.
+func (s *positionSlider) setMarks(marks []float64) {
+	if sameValues(s.marks, marks) {
+		return
+	}
+	s.marks = marks
+	s.Refresh()
+}
+
+// sameValues returns true if the two lists have the same values.
+func sameValues(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// positionSliderRenderer draws the marks over the track of the slider renderer.
+type positionSliderRenderer struct {
+	fyne.WidgetRenderer
+	slider *positionSlider
+	marks  []*canvas.Rectangle
+}
+
+// Layout implements the fyne.WidgetRenderer interface. The marks are placed on the track.
+func (r *positionSliderRenderer) Layout(size fyne.Size) {
+	r.WidgetRenderer.Layout(size)
+
+	s := r.slider
+	pad := s.endOffset()
+	width := size.Width - pad*2
+	if width <= 0 || s.Max <= s.Min {
+		return
+	}
+	markSize := fyne.NewSize(theme.InputBorderSize()*2, size.Height/3)
+	for i, mark := range r.marks {
+		ratio := float32((s.marks[i] - s.Min) / (s.Max - s.Min))
+		mark.Resize(markSize)
+		mark.Move(fyne.NewPos(pad+ratio*width-markSize.Width/2, (size.Height-markSize.Height)/2))
+	}
+}
+
+// Objects implements the fyne.WidgetRenderer interface. The marks are drawn under the thumb.
+func (r *positionSliderRenderer) Objects() []fyne.CanvasObject {
+	objects := r.WidgetRenderer.Objects()
+	if len(r.marks) == 0 || len(objects) < 2 {
+		return objects
+	}
+	// the track and the active part of the track are the first objects of the slider
+	all := append([]fyne.CanvasObject{}, objects[:2]...)
+	for _, mark := range r.marks {
+		all = append(all, mark)
+	}
+	return append(all, objects[2:]...)
+}
+
+// Refresh implements the fyne.WidgetRenderer interface. It creates a rectangle per mark.
+func (r *positionSliderRenderer) Refresh() {
+	marks := r.slider.marks
+	for len(r.marks) < len(marks) {
+		r.marks = append(r.marks, canvas.NewRectangle(theme.PrimaryColor()))
+	}
+	r.marks = r.marks[:len(marks)]
+	for _, mark := range r.marks {
+		mark.FillColor = theme.PrimaryColor()
+		mark.Refresh()
+	}
+	r.WidgetRenderer.Refresh()
+	r.Layout(r.slider.Size())
+}
@@ -0,0 +1,169 @@
+package video
+
+import (
+	"image"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/metal3d/fyne-streamer/internal/grabber"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+)
+
+const (
+	// previewWidth and previewHeight are the size of the preview frames.
+	previewWidth  = 160
+	previewHeight = 90
+
+	// previewPrecision is the precision of the preview positions, the cache uses
+	// one frame per previewPrecision.
+	previewPrecision = time.Second
+
+	// previewCacheSize is the maximum number of frames in the cache.
+	previewCacheSize = 300
+)
+
+// previewer grabs low resolution frames of a location in a secondary pipeline, and keeps
+// them in a cache. Only the last requested position is grabbed, so that a quick move
+// of the pointer doesn't stack the requests.
+type previewer struct {
+	mu      sync.Mutex
+	uri     fyne.URI
+	grabber *grabber.Grabber
+	cache   map[time.Duration]image.Image
+	wanted  time.Duration
+	busy    bool
+
+	// disabled is true when the location can't be previewed, it is checked once per location.
+	disabled bool
+
+	// onReady is called (in a goroutine) when a frame is grabbed.
+	onReady func(pos time.Duration, img image.Image)
+}
+
+// newPreviewer creates an empty previewer.
+func newPreviewer(onReady func(time.Duration, image.Image)) *previewer {
+	return &previewer{
+		cache:   map[time.Duration]image.Image{},
+		onReady: onReady,
+	}
+}
+
+// close releases the secondary pipeline and the cache.
+func (p *previewer) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.setURI(nil)
+}
+
+// frameAt returns the cached frame at the given position (rounded to previewPrecision), the
+// rounded position, and false if the location can't be previewed. If the frame is not in cache,
+// it is requested and nil is returned. Then onReady is called when the frame is ready.
+func (p *previewer) frameAt(u fyne.URI, pos time.Duration) (image.Image, time.Duration, bool) {
+	pos = pos.Truncate(previewPrecision)
+	if u == nil {
+		return nil, pos, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.uri == nil || p.uri.String() != u.String() {
+		p.setURI(u)
+	}
+	if p.disabled {
+		return nil, pos, false
+	}
+	if img, ok := p.cache[pos]; ok {
+		return img, pos, true
+	}
+
+	p.wanted = pos
+	if !p.busy {
+		p.busy = true
+		go p.grab()
+	}
+	return nil, pos, true
+}
+
+// grab gets the wanted frames until there is no more request.
+func (p *previewer) grab() {
+	for {
+		p.mu.Lock()
+		pos, u := p.wanted, p.uri
+		if _, ok := p.cache[pos]; ok || u == nil {
+			p.busy = false
+			p.mu.Unlock()
+			return
+		}
+		if p.grabber == nil {
+			// prerolling can take a while, do not lock the callers
+			p.mu.Unlock()
+			g, err := grabber.New(u, previewWidth, previewHeight, grabber.DefaultTimeout)
+			p.mu.Lock()
+			if err != nil {
+				if p.uri == u {
+					// do not retry on each move of the pointer
+					fyne.LogError("Failed to create the preview pipeline", err)
+					p.disabled = true
+				}
+				p.busy = false
+				p.mu.Unlock()
+				return
+			}
+			if p.uri != u {
+				// the location changed while prerolling
+				g.Close()
+				p.mu.Unlock()
+				continue
+			}
+			p.grabber = g
+		}
+		g := p.grabber
+		p.mu.Unlock()
+
+		// the grabber is only used here, while busy is true
+		img, err := g.FrameAt(pos)
+
+		p.mu.Lock()
+		ready := false
+		switch {
+		case p.grabber != g:
+			// the location changed while grabbing
+			g.Close()
+		case err != nil:
+			fyne.LogError("Failed to get the preview frame", err)
+			p.busy = false
+			p.mu.Unlock()
+			return
+		default:
+			if len(p.cache) >= previewCacheSize {
+				p.cache = map[time.Duration]image.Image{}
+			}
+			p.cache[pos] = img
+			ready = true
+		}
+		p.mu.Unlock()
+
+		if ready && p.onReady != nil {
+			p.onReady(pos, img)
+		}
+	}
+}
+
+// setURI changes the location, the secondary pipeline and the cache are released. The preview is
+// disabled if the grabber can't read the location. The lock must be held by the caller.
+func (p *previewer) setURI(u fyne.URI) {
+	if p.grabber != nil && !p.busy {
+		// if busy, the grab goroutine closes it
+		p.grabber.Close()
+	}
+	p.grabber = nil
+	p.uri = u
+	p.cache = map[time.Duration]image.Image{}
+	p.disabled = false
+	if u != nil {
+		_, err := utils.SourceElement(u)
+		p.disabled = err != nil
+	}
+}
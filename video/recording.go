@@ -0,0 +1,454 @@
+package video
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+)
+
+// Names of the elements of the recordings.
+const (
+	videoTeeElementName    = "fyne-videotee"
+	audioTeeElementName    = "fyne-audiotee"
+	recordMuxElementName   = "fyne-recordmux"
+	recordSinkElementName  = "fyne-recordsink"
+	recordVideoElementName = "fyne-recordvideo"
+	recordAudioElementName = "fyne-recordaudio"
+)
+
+// recordTimeout is the time to insert the tees in the pipeline, and to finalize the files.
+const recordTimeout = 5 * time.Second
+
+// RecordFormat is the container and the codecs of a recording, see StartRecording.
+type RecordFormat int
+
+const (
+	// RecordAuto selects the format from the extension of the path, WebM if it is unknown.
+	RecordAuto RecordFormat = iota
+
+	// RecordWebM records VP8 and Vorbis in a WebM file.
+	RecordWebM
+
+	// RecordMatroska records H264 (if x264enc is installed, VP8 otherwise) and Vorbis in a
+	// Matroska file.
+	RecordMatroska
+
+	// RecordMP4 records H264 and AAC in a MP4 file, x264enc is needed.
+	RecordMP4
+)
+
+// RecordOptions are the settings of a recording, see StartRecording. The zero value records the
+// video and the audio in the format of the extension of the path.
+type RecordOptions struct {
+	Format       RecordFormat
+	VideoBitrate uint // in kbit/s, 0 for the default of the encoder
+	NoAudio      bool // records the video only
+}
+
+// recordEncoder is an encoder of the recordings.
+type recordEncoder struct {
+	factory    string
+	properties string
+	bitrate    string // the bitrate property, formatted with the bitrate in kbit/s
+	parser     string // element between the encoder and the muxer, empty if there is none
+}
+
+// recordFormats are the muxers and the encoders of the formats, the first installed encoder is
+// used.
+var recordFormats = []struct {
+	format     RecordFormat
+	extensions []string
+	muxer      string
+	video      []recordEncoder
+	audio      []recordEncoder
+}{
+	{
+		RecordWebM, []string{".webm"}, "webmmux",
+		[]recordEncoder{{"vp8enc", "deadline=1 cpu-used=4", "target-bitrate=%d000", ""}},
+		[]recordEncoder{{"vorbisenc", "", "", ""}},
+	},
+	{
+		RecordMatroska, []string{".mkv"}, "matroskamux",
+		[]recordEncoder{
+			{"x264enc", "tune=zerolatency speed-preset=veryfast", "bitrate=%d", "h264parse"},
+			{"vp8enc", "deadline=1 cpu-used=4", "target-bitrate=%d000", ""},
+		},
+		[]recordEncoder{{"vorbisenc", "", "", ""}},
+	},
+	{
+		RecordMP4, []string{".mp4", ".m4v"}, "mp4mux",
+		[]recordEncoder{{"x264enc", "tune=zerolatency speed-preset=veryfast", "bitrate=%d", "h264parse"}},
+		[]recordEncoder{
+			{"avenc_aac", "", "", "aacparse"},
+			{"fdkaacenc", "", "", "aacparse"},
+			{"voaacenc", "", "", "aacparse"},
+		},
+	},
+}
+
+// recording is the branch of the pipeline that records the stream in a file.
+type recording struct {
+	bin      *gst.Bin
+	branches []recordBranch
+	eos      chan struct{} // closed when the file sink receives the end of the stream
+}
+
+// recordBranch is a stream that is sent to the recording by a tee.
+type recordBranch struct {
+	tee  *gst.Element
+	pad  *gst.Pad // request pad of the tee
+	sink *gst.Pad // ghost pad of the recording bin
+}
+
+// IsRecording returns true if the stream is recorded, see StartRecording.
+func (v *Viewer) IsRecording() bool {
+	v.recordingLock.Lock()
+	defer v.recordingLock.Unlock()
+	return v.recording != nil
+}
+
+// StartRecording records the displayed video, and the audio if the stream has audio tracks, in the
+// file at the given path. The decoded frames are encoded again, so what is recorded is what is
+// displayed, with the video balance. The playback continues while recording, and StopRecording
+// finalizes the file.
+//
+// The pipeline must be playing to start a recording, that is stopped when another media is
+// opened.
+func (v *Viewer) StartRecording(path string, opts RecordOptions) error {
+	v.recordingLock.Lock()
+	defer v.recordingLock.Unlock()
+	if v.pipeline == nil {
+		return streamer.ErrNoPipeline
+	}
+	if v.recording != nil {
+		return streamer.ErrAlreadyRecording
+	}
+	// the tees are inserted while the data flows, a prerolled pipeline holds the pads
+	if v.pipeline.GetCurrentState() != gst.StatePlaying {
+		return fmt.Errorf("%w: the pipeline is not playing", streamer.ErrRecordingFailed)
+	}
+
+	videoPad, audioPad, err := v.recordedPads(opts)
+	if err != nil {
+		return err
+	}
+	description, err := recordBinDescription(path, opts, audioPad != nil)
+	if err != nil {
+		return err
+	}
+	bin, err := gst.NewBinFromString(description, false)
+	if err != nil {
+		return fmt.Errorf("%w: %v", streamer.ErrRecordingFailed, err)
+	}
+	sink, err := bin.GetElementByName(recordSinkElementName)
+	if err != nil {
+		return err
+	}
+	// the location is not in the description, that would remove the "#" of the path as comments
+	sink.SetArg("location", path)
+
+	rec := &recording{bin: bin, eos: make(chan struct{})}
+	sink.GetStaticPad("sink").AddProbe(gst.PadProbeTypeEventDownstream, func(pad *gst.Pad, info *gst.PadProbeInfo) gst.PadProbeReturn {
+		if event := info.GetEvent(); event != nil && event.Type() == gst.EventTypeEOS {
+			close(rec.eos)
+			return gst.PadProbeRemove
+		}
+		return gst.PadProbeOK
+	})
+
+	// the recording keeps its own state, so that it can be finalized while the pipeline plays
+	if err := v.pipeline.Add(bin.Element); err != nil {
+		return err
+	}
+	utils.SetLockedState(bin.Element, true)
+	if err := bin.SetState(gst.StatePlaying); err != nil {
+		v.pipeline.Remove(bin.Element)
+		return fmt.Errorf("%w: %v", streamer.ErrRecordingFailed, err)
+	}
+
+	// the recording starts at 0, whatever the running time of the pipeline
+	var offset int64
+	if clock := v.pipeline.GetClock(); clock != nil {
+		offset = int64(clock.GetTime()) - int64(v.pipeline.GetBaseTime())
+	}
+	taps := []struct {
+		pad    *gst.Pad
+		tee    string
+		branch string
+	}{
+		{videoPad, videoTeeElementName, recordVideoElementName},
+		{audioPad, audioTeeElementName, recordAudioElementName},
+	}
+	for _, tap := range taps {
+		if tap.pad == nil {
+			continue
+		}
+		branch, err := v.recordBranch(bin, tap.pad, tap.tee, tap.branch, offset)
+		rec.branches = append(rec.branches, branch)
+		if err != nil {
+			if err := v.finishRecording(rec); err != nil {
+				fyne.LogError("Failed to remove the recording", err)
+			}
+			return err
+		}
+	}
+	v.recording = rec
+	return nil
+}
+
+// StopRecording stops the recording and finalizes the file. The end of the stream is sent to the
+// recording only, the playback continues. It blocks until the file is written.
+func (v *Viewer) StopRecording() error {
+	v.recordingLock.Lock()
+	defer v.recordingLock.Unlock()
+	if v.recording == nil {
+		return streamer.ErrNotRecording
+	}
+	rec := v.recording
+	v.recording = nil
+	return v.finishRecording(rec)
+}
+
+// finishRecording unlinks the branches of the recording from the tees, ends their stream and
+// removes the recording from the pipeline once the file is finalized.
+func (v *Viewer) finishRecording(rec *recording) error {
+	var linked []recordBranch // the branches that could not be unlinked
+	for _, branch := range rec.branches {
+		branch := branch
+		if branch.pad == nil {
+			continue
+		}
+		err := runWhenIdle(branch.pad, func(pad *gst.Pad) error {
+			pad.Unlink(branch.sink)
+			branch.tee.ReleaseRequestPad(pad)
+			return nil
+		})
+		if err != nil {
+			fyne.LogError("Failed to unlink the recording", err)
+			linked = append(linked, branch)
+		}
+	}
+	for _, branch := range rec.branches {
+		if branch.sink != nil {
+			branch.sink.SendEvent(gst.NewEOSEvent())
+		}
+	}
+
+	var err error
+	if len(rec.branches) > 0 {
+		select {
+		case <-rec.eos:
+		case <-time.After(recordTimeout):
+			err = fmt.Errorf("%w: the file is not finalized", streamer.ErrRecordingFailed)
+		}
+	}
+	rec.bin.SetState(gst.StateNull)
+	v.pipeline.Remove(rec.bin.Element)
+	// the stopped bin doesn't accept data anymore, and removing it has unlinked its ghost pads
+	for _, branch := range linked {
+		branch.tee.ReleaseRequestPad(branch.pad)
+	}
+	return err
+}
+
+// recordedPads returns the pads of the pipeline whose streams are recorded: the pad that sends
+// the frames to the appsink, and the one of the spectrum element for the audio. The audio pad is
+// nil if there is no audio track, or if the options don't record it.
+func (v *Viewer) recordedPads(opts RecordOptions) (*gst.Pad, *gst.Pad, error) {
+	if v.appSink == nil {
+		return nil, nil, fmt.Errorf("%w: no appsink in the pipeline", streamer.ErrRecordingFailed)
+	}
+	// once the tee is inserted, this is its pad, see recordTee
+	videoPad := v.appSink.GetStaticPad("sink").GetPeer()
+	if videoPad == nil {
+		return nil, nil, fmt.Errorf("%w: the appsink is not linked", streamer.ErrRecordingFailed)
+	}
+
+	v.trackLock.Lock()
+	tracks := len(v.audioTracks)
+	v.trackLock.Unlock()
+	if opts.NoAudio || tracks == 0 {
+		return videoPad, nil, nil
+	}
+	spectrum, err := v.pipeline.GetElementByName(streamer.SpectrumElementName)
+	if err != nil || spectrum == nil {
+		return videoPad, nil, nil
+	}
+	return videoPad, spectrum.GetStaticPad("src"), nil
+}
+
+// recordBranch links the pad to the sink of the recording bin through a tee, that is inserted
+// after the pad the first time.
+func (v *Viewer) recordBranch(bin *gst.Bin, pad *gst.Pad, teeName, sinkName string, offset int64) (recordBranch, error) {
+	tee, err := v.recordTee(pad, teeName)
+	if err != nil {
+		return recordBranch{}, err
+	}
+	element, err := bin.GetElementByName(sinkName)
+	if err != nil {
+		return recordBranch{}, err
+	}
+	ghost := gst.NewGhostPad(sinkName, element.GetStaticPad("sink"))
+	ghost.SetOffset(-offset)
+	bin.AddPad(ghost.Pad)
+
+	branch := recordBranch{tee: tee, sink: ghost.Pad}
+	branch.pad = tee.GetRequestPad("src_%u")
+	if ret := branch.pad.Link(ghost.Pad); ret != gst.PadLinkOK {
+		return branch, fmt.Errorf("%w: link returned %v", streamer.ErrRecordingFailed, ret)
+	}
+	return branch, nil
+}
+
+// recordTee returns the tee that sends the stream of the pad to the recordings. It is inserted
+// between the pad and its peer the first time, and kept for the next recordings.
+func (v *Viewer) recordTee(pad *gst.Pad, name string) (*gst.Element, error) {
+	if tee, err := v.pipeline.GetElementByName(name); err == nil && tee != nil {
+		return tee, nil
+	}
+	peer := pad.GetPeer()
+	if peer == nil {
+		return nil, fmt.Errorf("%w: the pad is not linked", streamer.ErrRecordingFailed)
+	}
+	tee, err := gst.NewElementWithName("tee", name)
+	if err != nil {
+		return nil, err
+	}
+	tee.SetProperty("allow-not-linked", true)
+	if err := v.pipeline.Add(tee); err != nil {
+		return nil, err
+	}
+
+	// the pad is relinked when no data flows through it
+	err = runWhenIdle(pad, func(pad *gst.Pad) error {
+		pad.Unlink(peer)
+		tee.SyncStateWithParent()
+		if ret := pad.Link(tee.GetStaticPad("sink")); ret != gst.PadLinkOK {
+			pad.Link(peer)
+			return fmt.Errorf("%w: link returned %v", streamer.ErrRecordingFailed, ret)
+		}
+		if ret := tee.GetRequestPad("src_%u").Link(peer); ret != gst.PadLinkOK {
+			return fmt.Errorf("%w: link returned %v", streamer.ErrRecordingFailed, ret)
+		}
+		return nil
+	})
+	if err != nil && !tee.GetStaticPad("sink").IsLinked() {
+		// the next recording inserts a new tee
+		tee.SetState(gst.StateNull)
+		v.pipeline.Remove(tee)
+		return nil, err
+	}
+	return tee, err
+}
+
+// runWhenIdle calls f in an idle probe of the pad, when no data flows through it, and returns its
+// error. If the pad is still busy after recordTimeout, e.g. when a prerolled sink holds it, the
+// probe is removed and f is never called.
+func runWhenIdle(pad *gst.Pad, f func(pad *gst.Pad) error) error {
+	var lock sync.Mutex
+	called, cancelled := false, false
+	done := make(chan error, 1)
+	id := pad.AddProbe(gst.PadProbeTypeIdle, func(pad *gst.Pad, info *gst.PadProbeInfo) gst.PadProbeReturn {
+		lock.Lock()
+		defer lock.Unlock()
+		if cancelled {
+			return gst.PadProbeOK // removed by the timeout
+		}
+		called = true
+		done <- f(pad)
+		return gst.PadProbeRemove
+	})
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(recordTimeout):
+	}
+
+	lock.Lock()
+	cancelled = !called
+	lock.Unlock()
+	if !cancelled {
+		// called while timing out
+		return <-done
+	}
+	pad.RemoveProbe(id)
+	return fmt.Errorf("%w: the stream is blocked", streamer.ErrRecordingFailed)
+}
+
+// recordBinDescription returns the description of the recording bin, with the encoders of the
+// format of the options.
+func recordBinDescription(path string, opts RecordOptions, audio bool) (string, error) {
+	format := opts.Format
+	if format == RecordAuto {
+		format = RecordWebM
+		ext := strings.ToLower(filepath.Ext(path))
+		for _, known := range recordFormats {
+			for _, extension := range known.extensions {
+				if extension == ext {
+					format = known.format
+				}
+			}
+		}
+	}
+	for _, known := range recordFormats {
+		if known.format != format {
+			continue
+		}
+		if gst.Find(known.muxer) == nil {
+			return "", fmt.Errorf("%w: %s is not installed", streamer.ErrNoRecordEncoder, known.muxer)
+		}
+		video := findRecordEncoder(known.video, opts.VideoBitrate)
+		if video == "" {
+			return "", fmt.Errorf("%w: no video encoder for %s", streamer.ErrNoRecordEncoder, known.muxer)
+		}
+		description := fmt.Sprintf(`
+        queue name=%s max-size-buffers=0 max-size-bytes=0 max-size-time=2000000000 leaky=downstream !
+        videoconvert !
+        %s !
+        %s name=%s !
+        filesink name=%s async=false`,
+			recordVideoElementName, video, known.muxer, recordMuxElementName, recordSinkElementName)
+		if !audio {
+			return description, nil
+		}
+		audioEncoder := findRecordEncoder(known.audio, 0)
+		if audioEncoder == "" {
+			fyne.LogError("Failed to record the audio", fmt.Errorf("%w: no audio encoder for %s", streamer.ErrNoRecordEncoder, known.muxer))
+			return description, nil
+		}
+		return description + fmt.Sprintf(`
+        queue name=%s max-size-buffers=0 max-size-bytes=0 max-size-time=2000000000 leaky=downstream !
+        audioconvert !
+        audioresample !
+        %s !
+        %s.`,
+			recordAudioElementName, audioEncoder, recordMuxElementName), nil
+	}
+	return "", fmt.Errorf("%w: unknown format %d", streamer.ErrNoRecordEncoder, format)
+}
+
+// findRecordEncoder returns the description of the first installed encoder, followed by its
+// parser. It is empty if none is installed.
+func findRecordEncoder(encoders []recordEncoder, bitrate uint) string {
+	for _, encoder := range encoders {
+		if gst.Find(encoder.factory) == nil {
+			continue
+		}
+		description := strings.TrimSpace(encoder.factory + " " + encoder.properties)
+		if bitrate > 0 && encoder.bitrate != "" {
+			description += " " + fmt.Sprintf(encoder.bitrate, bitrate)
+		}
+		if encoder.parser != "" {
+			description += " ! " + encoder.parser
+		}
+		return description
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package video
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordBinDescription(t *testing.T) {
+	setup(t)
+	if gst.Find("webmmux") == nil || gst.Find("vp8enc") == nil {
+		t.Skip("webmmux and vp8enc are needed to record")
+	}
+	description, err := recordBinDescription("clip.unknown", RecordOptions{VideoBitrate: 500}, false)
+	assert.Nil(t, err)
+	assert.Contains(t, description, "webmmux")
+	assert.Contains(t, description, "target-bitrate=500000")
+	assert.NotContains(t, description, recordAudioElementName)
+
+	_, err = recordBinDescription("clip.webm", RecordOptions{Format: RecordFormat(42)}, true)
+	assert.True(t, errors.Is(err, streamer.ErrNoRecordEncoder))
+}
+
+func TestRecording(t *testing.T) {
+	setup(t)
+	if gst.Find("webmmux") == nil || gst.Find("vp8enc") == nil {
+		t.Skip("webmmux and vp8enc are needed to record")
+	}
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	path := filepath.Join(t.TempDir(), "record.webm")
+
+	assert.True(t, errors.Is(video.StartRecording(path, RecordOptions{}), streamer.ErrNoPipeline))
+	err := video.Open(storage.NewFileURI(_testVideoFile))
+	assert.Nil(t, err)
+
+	// the prerolled pipeline can't be recorded, and is left as is
+	err = video.Pause()
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	err = video.StartRecording(path, RecordOptions{})
+	assert.True(t, errors.Is(err, streamer.ErrRecordingFailed))
+	assert.False(t, video.IsRecording())
+	tee, _ := video.pipeline.GetElementByName(videoTeeElementName)
+	assert.Nil(t, tee)
+
+	err = video.Play()
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	assert.True(t, errors.Is(video.StopRecording(), streamer.ErrNotRecording))
+
+	err = video.StartRecording(path, RecordOptions{})
+	assert.Nil(t, err)
+	assert.True(t, video.IsRecording())
+	assert.True(t, errors.Is(video.StartRecording(path, RecordOptions{}), streamer.ErrAlreadyRecording))
+	time.Sleep(time.Second)
+
+	err = video.StopRecording()
+	assert.Nil(t, err)
+	assert.False(t, video.IsRecording())
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.True(t, info.Size() > 0)
+
+	// the playback continues
+	_, state := video.pipeline.GetState(gst.StatePlaying, gst.ClockTime(time.Second))
+	assert.Equal(t, gst.StatePlaying, state)
+	video.Stop()
+}
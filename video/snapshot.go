@@ -0,0 +1,94 @@
+package video
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/internal/grabber"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+)
+
+// snapshotJPEGQuality is the quality of the jpeg snapshots.
+const snapshotJPEGQuality = 90
+
+// SaveSnapshot writes the currently displayed frame to the given path. The format
+// can be "png" or "jpeg" ("jpg"). If the format is empty, it is guessed from the
+// file extension.
+func (v *Viewer) SaveSnapshot(path, format string) error {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	format = strings.ToLower(format)
+	if format != "png" && format != "jpeg" && format != "jpg" {
+		return fmt.Errorf("%w: %q", streamer.ErrUnsupportedImageType, format)
+	}
+
+	img, err := v.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if format == "png" {
+		err = png.Encode(file, img)
+	} else {
+		err = jpeg.Encode(file, img, &jpeg.Options{Quality: snapshotJPEGQuality})
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Snapshot returns a copy of the frame that is currently displayed. The returned image
+// can be kept, it is not modified by the next frames.
+func (v *Viewer) Snapshot() (image.Image, error) {
+	v.frameLock.Lock()
+	defer v.frameLock.Unlock()
+
+	src := v.frame.Image
+	if src == nil {
+		return nil, streamer.ErrNoFrame
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, src.Bounds().Dx(), src.Bounds().Dy()))
+	draw.Draw(dst, dst.Bounds(), src, src.Bounds().Min, draw.Src)
+	return dst, nil
+}
+
+// SnapshotAt returns the frame at the given position. The frame is taken from another hidden
+// pipeline on the same location, so the playback is not disturbed.
+//
+// It only works with the files and the http(s) locations opened with Open(). It returns
+// ErrNoLocation for custom pipelines, readers and fyne storage locations, ErrSeekUnsupported
+// for live sources (RTSP, UDP, RTP and capture devices), and ErrUnsupportedScheme for the
+// locations of the other openers.
+func (v *Viewer) SnapshotAt(pos time.Duration) (image.Image, error) {
+	if v.uri == nil {
+		return nil, streamer.ErrNoLocation
+	}
+	if v.IsLive() {
+		return nil, streamer.ErrSeekUnsupported
+	}
+	if _, err := utils.SourceElement(v.uri); err != nil {
+		return nil, err
+	}
+	g, err := grabber.New(v.uri, 0, 0, grabber.DefaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer g.Close()
+	return g.FrameAt(pos)
+}
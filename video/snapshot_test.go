@@ -0,0 +1,86 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+
+	_, err := video.Snapshot()
+	assert.True(t, errors.Is(err, streamer.ErrNoFrame))
+
+	_, err = video.SnapshotAt(time.Second)
+	assert.True(t, errors.Is(err, streamer.ErrNoLocation))
+
+	err = video.Open(storage.NewFileURI(_testVideoFile))
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	video.SetOnNewFrame(func(at time.Duration) {
+		cancel()
+	})
+	video.Play()
+	<-ctx.Done()
+	video.Pause()
+
+	img, err := video.Snapshot()
+	assert.Nil(t, err)
+	assert.NotNil(t, img)
+
+	// the snapshot at a position uses its own pipeline
+	at, err := video.SnapshotAt(time.Second)
+	assert.Nil(t, err)
+	if assert.NotNil(t, at) {
+		assert.Equal(t, int(video.VideoSize().Width), at.Bounds().Dx())
+		assert.Equal(t, int(video.VideoSize().Height), at.Bounds().Dy())
+	}
+
+	dir := t.TempDir()
+	err = video.SaveSnapshot(filepath.Join(dir, "snapshot.png"), "")
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "snapshot.png"))
+	assert.Nil(t, err)
+
+	err = video.SaveSnapshot(filepath.Join(dir, "snapshot.bmp"), "")
+	assert.True(t, errors.Is(err, streamer.ErrUnsupportedImageType))
+}
+
+func TestSnapshotAtUnsupported(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+
+	// the live sources have no position
+	u, _ := storage.ParseURI("udp://127.0.0.1:15999")
+	err := video.Open(u)
+	assert.Nil(t, err)
+	_, err = video.SnapshotAt(time.Second)
+	assert.True(t, errors.Is(err, streamer.ErrSeekUnsupported))
+
+	// the hidden pipeline can't read the locations of the other openers
+	RegisterOpener("snapshot", func(fyne.URI, *OpenOptions) (string, error) {
+		return `videotestsrc name={{ .InputElementName }} ! videoconvert !
+		video/x-raw,format=RGBA ! appsink name={{ .AppSinkElementName }}`, nil
+	})
+	defer RegisterOpener("snapshot", nil)
+	u, _ = storage.ParseURI("snapshot://pattern")
+	err = video.Open(u)
+	assert.Nil(t, err)
+	_, err = video.SnapshotAt(time.Second)
+	assert.True(t, errors.Is(err, streamer.ErrUnsupportedScheme))
+}
@@ -0,0 +1,206 @@
+package video
+
+import (
+	"image/color"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/metal3d/fyne-streamer/internal/subtitles"
+)
+
+// subtitleMargin is the space between the subtitles and the bottom of the video, as a ratio of its height.
+const subtitleMargin = 0.06
+
+var _ fyne.Widget = (*subtitleOverlay)(nil)
+var _ fyne.WidgetRenderer = (*subtitleOverlayRenderer)(nil)
+
+// subtitleOverlay displays the active cues at the bottom of the video. It must be stacked over the frame.
+type subtitleOverlay struct {
+	widget.BaseWidget
+	viewer *Viewer
+
+	mu    sync.Mutex // protects the lines that are set by the streaming thread
+	cues  []subtitles.Cue
+	lines []subtitles.Line
+	style SubtitleStyle
+}
+
+// newSubtitleOverlay creates an empty overlay for the viewer.
+func newSubtitleOverlay(viewer *Viewer) *subtitleOverlay {
+	o := &subtitleOverlay{
+		viewer: viewer,
+		style:  DefaultSubtitleStyle,
+	}
+	o.ExtendBaseWidget(o)
+	return o
+}
+
+// CreateRenderer creates the renderer of the overlay.
+//
+// Implements: fyne.Widget
+func (o *subtitleOverlay) CreateRenderer() fyne.WidgetRenderer {
+	r := &subtitleOverlayRenderer{
+		parent:     o,
+		background: canvas.NewRectangle(color.Transparent),
+	}
+	r.background.CornerRadius = theme.InputRadiusSize()
+	r.Refresh()
+	return r
+}
+
+// content returns the displayed lines and the style.
+func (o *subtitleOverlay) content() ([]subtitles.Line, SubtitleStyle) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.lines, o.style
+}
+
+// setCues displays the given cues. Nothing is refreshed if the cues didn't change, as
+// it is called for each frame.
+func (o *subtitleOverlay) setCues(cues []subtitles.Cue) {
+	o.mu.Lock()
+	if sameCues(o.cues, cues) {
+		o.mu.Unlock()
+		return
+	}
+	o.cues = cues
+	o.lines = nil
+	for _, cue := range cues {
+		o.lines = append(o.lines, cue.Lines()...)
+	}
+	o.mu.Unlock()
+	o.Refresh()
+}
+
+// setStyle changes the style of the text and of the box.
+func (o *subtitleOverlay) setStyle(style SubtitleStyle) {
+	o.mu.Lock()
+	o.style = style
+	o.mu.Unlock()
+	o.Refresh()
+}
+
+// sameCues returns true if the two lists have the same cues.
+func sameCues(a, b []subtitles.Cue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// subtitleOverlayRenderer draws one text per line, over a background box.
+type subtitleOverlayRenderer struct {
+	parent     *subtitleOverlay
+	background *canvas.Rectangle
+	texts      []*canvas.Text
+}
+
+// Destroy is an internal function from the fyne.WidgetRenderer interface.
+func (r *subtitleOverlayRenderer) Destroy() { /* no op */ }
+
+// Layout implements the fyne.WidgetRenderer interface. The lines are centered at the bottom of
+// the video, that can be smaller than the widget if the frame keeps its aspect ratio.
+func (r *subtitleOverlayRenderer) Layout(size fyne.Size) {
+	if len(r.texts) == 0 {
+		return
+	}
+
+	// the area of the video in the frame
+	videoSize := r.parent.viewer.VideoSize()
+	area := size
+	if videoSize.Width > 0 && videoSize.Height > 0 && r.parent.viewer.Frame().FillMode == canvas.ImageFillContain {
+		ratio := videoSize.Width / videoSize.Height
+		if size.Width/size.Height > ratio {
+			area.Width = size.Height * ratio
+		} else {
+			area.Height = size.Width / ratio
+		}
+	}
+	bottom := (size.Height+area.Height)/2 - area.Height*subtitleMargin
+
+	var boxWidth, boxHeight float32
+	for _, text := range r.texts {
+		textSize := text.MinSize()
+		if textSize.Width > boxWidth {
+			boxWidth = textSize.Width
+		}
+		boxHeight += textSize.Height
+	}
+	padding := theme.Padding()
+	boxWidth += padding * 2
+	boxHeight += padding * 2
+
+	top := bottom - boxHeight
+	r.background.Resize(fyne.NewSize(boxWidth, boxHeight))
+	r.background.Move(fyne.NewPos((size.Width-boxWidth)/2, top))
+
+	y := top + padding
+	for _, text := range r.texts {
+		textSize := text.MinSize()
+		text.Resize(textSize)
+		text.Move(fyne.NewPos((size.Width-textSize.Width)/2, y))
+		y += textSize.Height
+	}
+}
+
+// MinSize implements the fyne.WidgetRenderer interface. The overlay doesn't change the size of the video.
+func (r *subtitleOverlayRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(0, 0)
+}
+
+// Objects implements the fyne.WidgetRenderer interface.
+func (r *subtitleOverlayRenderer) Objects() []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{r.background}
+	for _, text := range r.texts {
+		objects = append(objects, text)
+	}
+	return objects
+}
+
+// Refresh implements the fyne.WidgetRenderer interface. It creates the texts of the lines with the style.
+func (r *subtitleOverlayRenderer) Refresh() {
+	lines, style := r.parent.content()
+
+	textColor := style.Color
+	if textColor == nil {
+		textColor = color.White
+	}
+	textSize := style.TextSize
+	if textSize <= 0 {
+		textSize = theme.TextSize() * 1.5
+	}
+
+	for len(r.texts) < len(lines) {
+		text := canvas.NewText("", textColor)
+		text.Alignment = fyne.TextAlignCenter
+		r.texts = append(r.texts, text)
+	}
+	r.texts = r.texts[:len(lines)]
+	for i, line := range lines {
+		r.texts[i].Text = line.Text
+		r.texts[i].Color = textColor
+		r.texts[i].TextSize = textSize
+		r.texts[i].TextStyle = fyne.TextStyle{Italic: line.Italic, Bold: line.Bold}
+	}
+
+	if style.Background == nil || len(lines) == 0 {
+		r.background.Hide()
+	} else {
+		r.background.FillColor = style.Background
+		r.background.Show()
+	}
+
+	r.Layout(r.parent.Size())
+	r.background.Refresh()
+	for _, text := range r.texts {
+		text.Refresh()
+	}
+}
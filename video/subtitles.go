@@ -0,0 +1,178 @@
+package video
+
+import (
+	"image/color"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"github.com/go-gst/go-gst/gst"
+	"github.com/go-gst/go-gst/gst/app"
+	"github.com/metal3d/fyne-streamer/internal/subtitles"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+)
+
+const (
+	// embeddedCueDuration is the duration of an embedded cue that has no duration. It is
+	// shortened when the next cue arrives.
+	embeddedCueDuration = 5 * time.Second
+
+	// maxEmbeddedCues is the number of received embedded cues that are kept, the older ones
+	// are not displayed anymore.
+	maxEmbeddedCues = 16
+)
+
+// SubtitleStyle is the style of the subtitles displayed over the frame.
+type SubtitleStyle struct {
+	// TextSize is the size of the text. If 0, the text size of the theme is used with a 1.5 ratio.
+	TextSize float32
+
+	// Color is the color of the text. If nil, the text is white.
+	Color color.Color
+
+	// Background is the color of the box behind the text. If nil, there is no box.
+	Background color.Color
+}
+
+// DefaultSubtitleStyle is the style of the subtitles if SetSubtitleStyle is not called. The text
+// is white on a semi transparent black box.
+var DefaultSubtitleStyle = SubtitleStyle{
+	Color:      color.White,
+	Background: color.NRGBA{A: 0xA0},
+}
+
+// ClearSubtitles removes the loaded subtitles.
+func (v *Viewer) ClearSubtitles() {
+	v.subtitleLock.Lock()
+	v.subtitleCues = nil
+	v.subtitleLock.Unlock()
+	v.subtitleOverlay.setCues(nil)
+}
+
+// LoadSubtitles reads the SRT or WebVTT file at the given location, and displays the subtitles
+// over the frame. The subtitles are removed when another media is opened, so they must be loaded
+// after Open.
+func (v *Viewer) LoadSubtitles(u fyne.URI) error {
+	reader, err := storage.Reader(u)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	cues, err := subtitles.Parse(reader)
+	if err != nil {
+		return err
+	}
+
+	v.subtitleLock.Lock()
+	v.subtitleCues = cues
+	v.subtitleLock.Unlock()
+
+	v.refreshSubtitles()
+	return nil
+}
+
+// SetSubtitleOffset shifts the subtitles in time. A positive offset displays the subtitles later,
+// a negative offset displays them earlier.
+func (v *Viewer) SetSubtitleOffset(offset time.Duration) {
+	v.subtitleLock.Lock()
+	v.subtitleOffset = offset
+	v.subtitleLock.Unlock()
+
+	v.refreshSubtitles()
+}
+
+// SetSubtitleStyle changes the font size, the color and the background box of the subtitles.
+func (v *Viewer) SetSubtitleStyle(style SubtitleStyle) {
+	v.subtitleOverlay.setStyle(style)
+}
+
+// SubtitleOffset returns the offset of the subtitles, see SetSubtitleOffset.
+func (v *Viewer) SubtitleOffset() time.Duration {
+	v.subtitleLock.Lock()
+	defer v.subtitleLock.Unlock()
+	return v.subtitleOffset
+}
+
+// clearEmbeddedCues removes the received cues of the embedded subtitle track.
+func (v *Viewer) clearEmbeddedCues() {
+	v.subtitleLock.Lock()
+	v.embeddedCues = nil
+	v.subtitleLock.Unlock()
+	v.refreshSubtitles()
+}
+
+// newSubtitleFunc is called when the subtitle sink receives the text of a cue of the selected
+// track. This is a callback on the subtitle appsink.
+func (v *Viewer) newSubtitleFunc(appSink *app.Sink) gst.FlowReturn {
+	sample := appSink.PullSample()
+	if sample == nil {
+		return gst.FlowEOS
+	}
+	if v.SubtitleTrack() < 0 {
+		// the first track goes through the selector, even if the subtitles are disabled
+		return gst.FlowOK
+	}
+
+	buffer := sample.GetBuffer()
+	if buffer == nil {
+		return gst.FlowError
+	}
+	start := buffer.PresentationTimestamp().AsDuration()
+	if start == nil {
+		return gst.FlowOK
+	}
+	end := *start + embeddedCueDuration
+	if duration := buffer.Duration().AsDuration(); duration != nil {
+		end = *start + *duration
+	}
+
+	mapInfo := buffer.Map(gst.MapRead)
+	if mapInfo == nil {
+		return gst.FlowError
+	}
+	// the text is copied, the buffer can be unmapped
+	text := strings.TrimRight(string(utils.MappedBytes(mapInfo)), "\x00")
+	buffer.Unmap()
+
+	v.subtitleLock.Lock()
+	last := len(v.embeddedCues) - 1
+	switch {
+	case last >= 0 && v.embeddedCues[last].Start > *start:
+		// the pipeline did seek backward
+		v.embeddedCues = nil
+	case last >= 0 && v.embeddedCues[last].End > *start:
+		v.embeddedCues[last].End = *start
+	}
+	v.embeddedCues = append(v.embeddedCues, subtitles.Cue{Start: *start, End: end, Text: text})
+	if len(v.embeddedCues) > maxEmbeddedCues {
+		v.embeddedCues = v.embeddedCues[len(v.embeddedCues)-maxEmbeddedCues:]
+	}
+	v.subtitleLock.Unlock()
+
+	return gst.FlowOK
+}
+
+// refreshSubtitles displays the cues of the current position, e.g. when the cues changed.
+func (v *Viewer) refreshSubtitles() {
+	pos, _ := v.CurrentPosition() // 0 if there is no pipeline
+	v.updateSubtitles(pos)
+}
+
+// updateSubtitles displays the cues of the given position of the stream, from the external
+// subtitles and from the embedded subtitle track.
+func (v *Viewer) updateSubtitles(pos time.Duration) {
+	v.subtitleLock.Lock()
+	if len(v.subtitleCues) == 0 && len(v.embeddedCues) == 0 {
+		v.subtitleLock.Unlock()
+		v.subtitleOverlay.setCues(nil)
+		return
+	}
+	cues := subtitles.Active(v.subtitleCues, pos-v.subtitleOffset)
+	// the embedded cues are already synchronized with the stream
+	cues = append(cues, subtitles.Active(v.embeddedCues, pos)...)
+	v.subtitleLock.Unlock()
+
+	v.subtitleOverlay.setCues(cues)
+}
@@ -0,0 +1,88 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubtitles(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+
+	path := filepath.Join(t.TempDir(), "test.srt")
+	err := os.WriteFile(path, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n"), 0644)
+	assert.Nil(t, err)
+
+	err = video.LoadSubtitles(storage.NewFileURI(path))
+	assert.Nil(t, err)
+
+	video.updateSubtitles(1500 * time.Millisecond)
+	lines, _ := video.subtitleOverlay.content()
+	assert.Len(t, lines, 1)
+	assert.Equal(t, "Hello", lines[0].Text)
+
+	video.updateSubtitles(2500 * time.Millisecond)
+	lines, _ = video.subtitleOverlay.content()
+	assert.Empty(t, lines)
+
+	// the subtitles are displayed 1 second later
+	video.SetSubtitleOffset(time.Second)
+	video.updateSubtitles(2500 * time.Millisecond)
+	lines, _ = video.subtitleOverlay.content()
+	assert.Len(t, lines, 1)
+
+	video.ClearSubtitles()
+	lines, _ = video.subtitleOverlay.content()
+	assert.Empty(t, lines)
+
+	err = video.LoadSubtitles(storage.NewFileURI(filepath.Join(t.TempDir(), "missing.srt")))
+	assert.NotNil(t, err)
+}
+
+func TestSubtitleTracks(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+
+	// the subtitle file is decoded as a text track
+	path := filepath.Join(t.TempDir(), "test.srt")
+	err := os.WriteFile(path, []byte("1\n00:00:00,000 --> 00:00:05,000\nHello\n"), 0644)
+	assert.Nil(t, err)
+
+	err = video.SetPipelineFromString(fmt.Sprintf(`
+    videotestsrc num-buffers=100 ! video/x-raw,width=160,height=120 !
+    videoconvert ! video/x-raw,format=RGBA !
+    appsink name={{ .AppSinkElementName }}
+
+    filesrc location=%q ! decodebin name={{ .DecodeElementName }}
+
+    input-selector name={{ .SubtitleSelectorElementName }} !
+    appsink name={{ .SubtitleSinkElementName }} sync=false async=false`, path))
+	assert.Nil(t, err)
+	assert.Equal(t, -1, video.SubtitleTrack())
+
+	err = video.SelectSubtitleTrack(0)
+	assert.True(t, errors.Is(err, streamer.ErrInvalidTrack))
+
+	err = video.Pause()
+	assert.Nil(t, err)
+	time.Sleep(time.Second)
+
+	assert.Len(t, video.SubtitleTracks(), 1)
+	err = video.SelectSubtitleTrack(0)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, video.SubtitleTrack())
+
+	video.DisableSubtitles()
+	assert.Equal(t, -1, video.SubtitleTrack())
+}
@@ -0,0 +1,216 @@
+package video
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/internal/utils"
+)
+
+// TrackInfo describes a track of the media, e.g. an audio or a subtitle track in a given language.
+type TrackInfo struct {
+	Index    int    // the index to give to the Select...Track methods
+	Language string // ISO 639 language code, e.g. "en", empty if unknown
+	Codec    string // codec of the track, e.g. "MPEG-4 AAC", empty if unknown
+	Channels int    // number of audio channels, 0 if unknown or for subtitles
+}
+
+// String returns a label of the track for the menus, e.g. "en - MPEG-4 AAC, 2 channels".
+func (t TrackInfo) String() string {
+	label := fmt.Sprintf("Track %d", t.Index+1)
+	if t.Language != "" {
+		label = t.Language
+	}
+	var details []string
+	if t.Codec != "" {
+		details = append(details, t.Codec)
+	}
+	if t.Channels > 0 {
+		details = append(details, fmt.Sprintf("%d channels", t.Channels))
+	}
+	if len(details) > 0 {
+		label += " - " + strings.Join(details, ", ")
+	}
+	return label
+}
+
+// AudioTrack returns the index of the selected audio track, or -1 if there is no track.
+func (v *Viewer) AudioTrack() int {
+	v.trackLock.Lock()
+	defer v.trackLock.Unlock()
+	if len(v.audioTracks) == 0 {
+		return -1
+	}
+	return v.audioTrack
+}
+
+// AudioTracks returns the audio tracks of the media, in the order of the container. The tracks
+// are found while the pipeline is prerolling, and the language and codec are known when the first
+// data are decoded.
+//
+// The pipeline must have an "input-selector" element named with AudioSelectorElementName after
+// the decode element, which is the case of the default pipelines.
+func (v *Viewer) AudioTracks() []TrackInfo {
+	v.trackLock.Lock()
+	defer v.trackLock.Unlock()
+	return tracksInfo(v.audioTracks, gst.TagAudioCodec)
+}
+
+// SelectAudioTrack changes the audio track to the track at the given index (see AudioTracks).
+// The pipeline is not reopened, so the position and the state are kept.
+func (v *Viewer) SelectAudioTrack(i int) error {
+	if v.pipeline == nil {
+		return streamer.ErrNoPipeline
+	}
+	selector, err := v.pipeline.GetElementByName(streamer.AudioSelectorElementName)
+	if err != nil || selector == nil {
+		return streamer.ErrNoTrackSelector
+	}
+
+	v.trackLock.Lock()
+	defer v.trackLock.Unlock()
+	if i < 0 || i >= len(v.audioTracks) {
+		return fmt.Errorf("%w: %d, there are %d audio tracks", streamer.ErrInvalidTrack, i, len(v.audioTracks))
+	}
+	if err := utils.SetPadProperty(selector, "active-pad", v.audioTracks[i]); err != nil {
+		return fmt.Errorf("failed to set active-pad property: %w", err)
+	}
+	v.audioTrack = i
+	return nil
+}
+
+// DisableSubtitles hides the embedded subtitles. The external subtitles are removed with ClearSubtitles.
+func (v *Viewer) DisableSubtitles() {
+	v.trackLock.Lock()
+	v.subtitleTrack = -1
+	v.trackLock.Unlock()
+	v.clearEmbeddedCues()
+}
+
+// SelectSubtitleTrack displays the embedded subtitle track at the given index (see SubtitleTracks)
+// over the frame. The pipeline is not reopened, so the position and the state are kept.
+func (v *Viewer) SelectSubtitleTrack(i int) error {
+	if v.pipeline == nil {
+		return streamer.ErrNoPipeline
+	}
+	selector, err := v.pipeline.GetElementByName(streamer.SubtitleSelectorElementName)
+	if err != nil || selector == nil {
+		return streamer.ErrNoTrackSelector
+	}
+
+	v.trackLock.Lock()
+	if i < 0 || i >= len(v.subtitleTracks) {
+		v.trackLock.Unlock()
+		return fmt.Errorf("%w: %d, there are %d subtitle tracks", streamer.ErrInvalidTrack, i, len(v.subtitleTracks))
+	}
+	if err := utils.SetPadProperty(selector, "active-pad", v.subtitleTracks[i]); err != nil {
+		v.trackLock.Unlock()
+		return fmt.Errorf("failed to set active-pad property: %w", err)
+	}
+	v.subtitleTrack = i
+	v.trackLock.Unlock()
+
+	// the cues of the previous track must not be displayed
+	v.clearEmbeddedCues()
+	return nil
+}
+
+// SubtitleTrack returns the index of the displayed embedded subtitle track, or -1 if the
+// subtitles are disabled. They are disabled by default.
+func (v *Viewer) SubtitleTrack() int {
+	v.trackLock.Lock()
+	defer v.trackLock.Unlock()
+	return v.subtitleTrack
+}
+
+// SubtitleTracks returns the embedded text subtitle tracks of the media, in the order of the
+// container. Bitmap subtitles (DVD, DVB or PGS subpictures) are not supported.
+//
+// The pipeline must have an "input-selector" element named with SubtitleSelectorElementName after
+// the decode element, and an "appsink" named with SubtitleSinkElementName after the selector, which
+// is the case of the default pipelines.
+func (v *Viewer) SubtitleTracks() []TrackInfo {
+	v.trackLock.Lock()
+	defer v.trackLock.Unlock()
+	return tracksInfo(v.subtitleTracks, gst.TagSubtitleCodec)
+}
+
+// audioTrackCount returns the number of audio tracks, without reading their information.
+func (v *Viewer) audioTrackCount() int {
+	v.trackLock.Lock()
+	defer v.trackLock.Unlock()
+	return len(v.audioTracks)
+}
+
+// subtitleTrackCount returns the number of subtitle tracks, without reading their information.
+func (v *Viewer) subtitleTrackCount() int {
+	v.trackLock.Lock()
+	defer v.trackLock.Unlock()
+	return len(v.subtitleTracks)
+}
+
+// registerTrackSelectors links the audio and text pads of the decode element to the audio and
+// subtitle selectors, if they exist in the pipeline.
+func (v *Viewer) registerTrackSelectors() {
+	decode, err := v.pipeline.GetElementByName(streamer.DecodeElementName)
+	if err != nil || decode == nil {
+		return
+	}
+	v.watchDecodedTracks(decode)
+}
+
+// watchDecodedTracks links the audio and text pads of a decodebin to their selectors.
+func (v *Viewer) watchDecodedTracks(decode *gst.Element) {
+	// the selectors are optional
+	audioSelector, _ := v.pipeline.GetElementByName(streamer.AudioSelectorElementName)
+	subtitleSelector, _ := v.pipeline.GetElementByName(streamer.SubtitleSelectorElementName)
+	if audioSelector == nil && subtitleSelector == nil {
+		return
+	}
+
+	_, err := decode.Connect("pad-added", func(self *gst.Element, pad *gst.Pad) {
+		mediaType := utils.PadMediaType(pad)
+		switch {
+		case audioSelector != nil && strings.HasPrefix(mediaType, "audio/"):
+			v.linkTrack(audioSelector, pad, &v.audioTracks)
+		case subtitleSelector != nil && strings.HasPrefix(mediaType, "text/"):
+			v.linkTrack(subtitleSelector, pad, &v.subtitleTracks)
+		}
+	})
+	if err != nil {
+		fyne.LogError("Failed to watch the decoded tracks", err)
+	}
+}
+
+// linkTrack links a new pad of the decode element to the selector, and adds the selector pad to
+// the tracks. The first track is the active one.
+func (v *Viewer) linkTrack(selector *gst.Element, pad *gst.Pad, tracks *[]*gst.Pad) {
+	sinkPad := selector.GetRequestPad("sink_%u")
+	if sinkPad == nil {
+		fyne.LogError("Failed to get a pad from the track selector", nil)
+		return
+	}
+	if ret := pad.Link(sinkPad); ret != gst.PadLinkOK {
+		fyne.LogError("Failed to link the track", fmt.Errorf("link returned %v", ret))
+		selector.ReleaseRequestPad(sinkPad)
+		return
+	}
+	v.trackLock.Lock()
+	*tracks = append(*tracks, sinkPad)
+	v.trackLock.Unlock()
+}
+
+// tracksInfo returns the information of the tracks, the codec is read from the given tag.
+func tracksInfo(pads []*gst.Pad, codecTag gst.Tag) []TrackInfo {
+	tracks := make([]TrackInfo, len(pads))
+	for i, pad := range pads {
+		tracks[i] = TrackInfo{Index: i}
+		tracks[i].Language, _ = utils.PadTagString(pad, gst.TagLanguageCode)
+		tracks[i].Codec, _ = utils.PadTagString(pad, codecTag)
+		tracks[i].Channels, _ = utils.PadCapsInt(pad, "channels")
+	}
+	return tracks
+}
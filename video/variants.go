@@ -0,0 +1,144 @@
+package video
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/internal/adaptive"
+)
+
+// adaptiveDemuxers are the GStreamer elements that download the variants of the adaptive streams.
+var adaptiveDemuxers = []string{"hlsdemux", "hlsdemux2", "dashdemux", "dashdemux2"}
+
+// defaultBitrateLimit is the default "bitrate-limit" of the adaptive demuxers: the part of the
+// connection speed that the automatic selection uses.
+const defaultBitrateLimit float32 = 0.8
+
+// Variant is a version of an adaptive stream (HLS or DASH) at a given bitrate.
+type Variant struct {
+	Bandwidth uint // in bits per second
+	Width     int  // 0 if unknown or for audio only variants
+	Height    int
+	Codecs    string // e.g. "avc1.4d401f,mp4a.40.2", empty if unknown
+}
+
+// SelectedVariant returns the index of the variant selected with SetVariant, or -1 if the variant
+// is selected automatically from the connection speed.
+func (v *Viewer) SelectedVariant() int {
+	v.variantLock.Lock()
+	defer v.variantLock.Unlock()
+	return v.variant
+}
+
+// SetAutoVariant lets the adaptive demuxer select the variant from the connection speed, this
+// is the default.
+func (v *Viewer) SetAutoVariant() error {
+	v.variantLock.Lock()
+	if len(v.variants) == 0 {
+		v.variantLock.Unlock()
+		return streamer.ErrNotAdaptive
+	}
+	v.variant = -1
+	v.variantLock.Unlock()
+	return v.applyVariant()
+}
+
+// SetVariant plays the variant at the given index (see Variants) whatever the connection speed.
+// The variant changes at the next fragment of the stream.
+func (v *Viewer) SetVariant(i int) error {
+	v.variantLock.Lock()
+	if len(v.variants) == 0 {
+		v.variantLock.Unlock()
+		return streamer.ErrNotAdaptive
+	}
+	if i < 0 || i >= len(v.variants) {
+		defer v.variantLock.Unlock()
+		return fmt.Errorf("%w: %d, there are %d variants", streamer.ErrInvalidVariant, i, len(v.variants))
+	}
+	v.variant = i
+	v.variantLock.Unlock()
+	return v.applyVariant()
+}
+
+// Variants returns the variants of the adaptive stream, sorted by bandwidth. It is empty if the
+// stream is not adaptive, if it is a HLS media playlist, or while the manifest is downloaded after
+// Open, see SetOnVariantsChanged.
+func (v *Viewer) Variants() []Variant {
+	v.variantLock.Lock()
+	defer v.variantLock.Unlock()
+	return append([]Variant(nil), v.variants...)
+}
+
+// applyVariant sets the connection speed of the adaptive demuxers to the bandwidth of the
+// selected variant, so that they don't select another one, or to 0 for the automatic selection.
+// The demuxers that are plugged later by the decodebin are configured when the stream prerolls.
+func (v *Viewer) applyVariant() error {
+	if v.pipeline == nil {
+		return streamer.ErrNoPipeline
+	}
+	v.variantLock.Lock()
+	speed, limit := uint(0), defaultBitrateLimit
+	if v.variant >= 0 && v.variant < len(v.variants) {
+		// in kbps, rounded up to select this variant and not the previous one
+		speed, limit = (v.variants[v.variant].Bandwidth+999)/1000, 1
+	}
+	v.variantLock.Unlock()
+
+	for _, demuxer := range v.adaptiveDemuxers() {
+		if err := demuxer.SetProperty("connection-speed", speed); err != nil {
+			return err
+		}
+		if err := demuxer.SetProperty("bitrate-limit", limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adaptiveDemuxers returns the adaptive demuxers plugged in the pipeline.
+func (v *Viewer) adaptiveDemuxers() []*gst.Element {
+	elements, err := v.pipeline.GetElementsRecursive()
+	if err != nil {
+		return nil
+	}
+	var demuxers []*gst.Element
+	for _, element := range elements {
+		factory := element.GetFactory()
+		if factory == nil {
+			continue
+		}
+		for _, name := range adaptiveDemuxers {
+			if factory.GetName() == name {
+				demuxers = append(demuxers, element)
+			}
+		}
+	}
+	return demuxers
+}
+
+// fetchVariants downloads the manifest of the adaptive stream to know its variants. They are
+// dropped if another media is opened in the meantime, i.e. if the fetch count has changed.
+func (v *Viewer) fetchVariants(u fyne.URI, kind adaptive.Kind, fetch int) {
+	found, err := adaptive.Fetch(u, kind)
+	if err != nil {
+		fyne.LogError("Failed to read the variants of the adaptive stream", err)
+		return
+	}
+	variants := make([]Variant, len(found))
+	for i, variant := range found {
+		variants[i] = Variant(variant)
+	}
+	v.variantLock.Lock()
+	if v.variantFetch != fetch {
+		v.variantLock.Unlock()
+		return
+	}
+	v.variants = variants
+	onVariants := v.onVariants
+	v.variantLock.Unlock()
+	if onVariants != nil {
+		onVariants(v.Variants())
+	}
+}
@@ -0,0 +1,118 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestHLS generates a HLS media playlist of 3 seconds with hlssink, and a master playlist
+// with 2 variants that use it.
+func newTestHLS(t *testing.T) string {
+	if gst.Find("x264enc") == nil || gst.Find("hlssink") == nil {
+		t.Skip("x264enc and hlssink are needed to generate the HLS stream")
+	}
+	dir := t.TempDir()
+	pipeline, err := gst.NewPipelineFromString(fmt.Sprintf(`
+    videotestsrc num-buffers=90 ! video/x-raw,width=320,height=240,framerate=30/1 !
+    x264enc key-int-max=30 ! h264parse ! mpegtsmux !
+    hlssink location=%q playlist-location=%q target-duration=1 max-files=0 playlist-length=0`,
+		filepath.Join(dir, "segment%05d.ts"),
+		filepath.Join(dir, "media.m3u8"),
+	))
+	assert.Nil(t, err)
+	assert.Nil(t, pipeline.SetState(gst.StatePlaying))
+	msg := pipeline.GetPipelineBus().TimedPopFiltered(gst.ClockTime(10*time.Second), gst.MessageEOS|gst.MessageError)
+	assert.NotNil(t, msg)
+	assert.Equal(t, gst.MessageEOS, msg.Type())
+	assert.Nil(t, pipeline.SetState(gst.StateNull))
+
+	master := `#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=320x240
+media.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=200000,RESOLUTION=320x240
+media.m3u8
+`
+	err = os.WriteFile(filepath.Join(dir, "master.m3u8"), []byte(master), 0644)
+	assert.Nil(t, err)
+	return dir
+}
+
+func TestVariants(t *testing.T) {
+	setup(t)
+	dir := newTestHLS(t)
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer server.Close()
+
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	changed := make(chan []Variant, 1)
+	video.SetOnVariantsChanged(func(variants []Variant) {
+		changed <- variants
+	})
+
+	u, err := storage.ParseURI(server.URL + "/master.m3u8")
+	assert.Nil(t, err)
+	err = video.Open(u)
+	assert.Nil(t, err)
+
+	// the manifest is read in the background
+	select {
+	case variants := <-changed:
+		assert.Len(t, variants, 2)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the variants are not read")
+	}
+	variants := video.Variants()
+	assert.Len(t, variants, 2)
+	assert.Equal(t, uint(200000), variants[0].Bandwidth)
+	assert.Equal(t, 320, variants[1].Width)
+	assert.Equal(t, -1, video.SelectedVariant())
+
+	err = video.SetVariant(2)
+	assert.True(t, errors.Is(err, streamer.ErrInvalidVariant))
+	err = video.SetVariant(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, video.SelectedVariant())
+
+	err = video.Play()
+	assert.Nil(t, err)
+	time.Sleep(time.Second)
+	assert.NotEmpty(t, video.adaptiveDemuxers())
+	speed, err := video.adaptiveDemuxers()[0].GetProperty("connection-speed")
+	assert.Nil(t, err)
+	assert.Equal(t, uint(800), speed)
+	pos, err := video.CurrentPosition()
+	assert.Nil(t, err)
+	assert.True(t, pos > 0)
+
+	err = video.SetAutoVariant()
+	assert.Nil(t, err)
+	assert.Equal(t, -1, video.SelectedVariant())
+	video.Stop()
+
+	// a media playlist has no variant
+	u, _ = storage.ParseURI(server.URL + "/media.m3u8")
+	err = video.Open(u)
+	assert.Nil(t, err)
+	select {
+	case variants := <-changed:
+		assert.Empty(t, variants)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the variants are not read")
+	}
+	assert.Empty(t, video.Variants())
+	err = video.SetVariant(0)
+	assert.True(t, errors.Is(err, streamer.ErrNotAdaptive))
+}
@@ -2,8 +2,10 @@ package video
 
 import (
 	"fmt"
+	"image"
 	"image/color"
 	"log"
+	"strconv"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -12,23 +14,28 @@ import (
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
-	"github.com/go-gst/go-gst/gst"
 	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/visualizer"
 )
 
 // autoHideDuration is the default duration of the auto hide of the controls.
 const autoHideDuration = 2 * time.Second
 
+// playbackRates are the rates proposed by the speed selector of the controls.
+var playbackRates = []float64{-1, 0.25, 0.5, 0.75, 1, 1.25, 1.5, 2, 4}
+
 var _ fyne.Widget = (*VideoControls)(nil)
 var _ fyne.WidgetRenderer = (*videoControlsRenderer)(nil)
 
 // VideoControls is the widget that displays the video controls (play, pause, fullscreen, etc.).
 type VideoControls struct {
 	widget.BaseWidget
-	viewer   *Viewer
-	timeStep time.Duration
-	renderer *videoControlsRenderer
-	onTapped func()
+	viewer           *Viewer
+	timeStep         time.Duration
+	renderer         *videoControlsRenderer
+	onTapped         func()
+	showFrameButtons bool
+	showLevelMeter   bool
 }
 
 // NewVideoControls creates a new video controls widget. It is used to control the video viewer.
@@ -75,22 +82,55 @@ func (vc *VideoControls) SetDuration(d time.Duration) {
 	vc.Refresh()
 }
 
+// SetFrameStepButtonsVisible shows or hides the buttons to go to the next or previous frame.
+// They are hidden by default.
+func (vc *VideoControls) SetFrameStepButtonsVisible(visible bool) {
+	vc.showFrameButtons = visible
+	if vc.renderer == nil {
+		return
+	}
+	vc.renderer.applyFrameButtonsVisibility()
+}
+
+// SetLevelMeterVisible shows or hides the audio level meter beside the volume slider. It is hidden
+// by default. The function set with the SetOnAudioLevel of the viewer is still called.
+func (vc *VideoControls) SetLevelMeterVisible(visible bool) {
+	vc.showLevelMeter = visible
+	if vc.renderer == nil {
+		return
+	}
+	vc.renderer.applyLevelMeterVisibility()
+}
+
 // videoControlsRenderer is the renderer of the video controls widget. This is the widget that
 // displays the buttons, sliders, background, etc.
 type videoControlsRenderer struct {
 	parent              *VideoControls
 	playbutton          *widget.Button
+	nextFrameButton     *widget.Button
+	previousFrameButton *widget.Button
 	muteButton          *widget.Button
+	levelMeter          *visualizer.LevelMeter
+	audioTrackButton    *widget.Button
+	subtitleTrackButton *widget.Button
+	speedSelect         *widget.Select
 	fullscreenButton    *widget.Button
 	videoControlsButton *widget.Button
 	timeText            *widget.Label
 	currentTime         time.Duration
 	totalTime           time.Duration
-	cursor              *widget.Slider
+	cursor              *positionSlider
 	controls            *fyne.Container
 	background          *canvas.Rectangle
 	isFullScreen        bool
 	manualSeeked        bool
+
+	// preview of the hovered position on the cursor
+	preview      *fyne.Container
+	previewImage *canvas.Image
+	previewTime  *canvas.Text
+	previewPos   time.Duration
+	previewer    *previewer
 }
 
 // newVideoControlsRenderer creates a new videoControlsRenderer.
@@ -110,7 +150,13 @@ func newVideoControlsRenderer(parent *VideoControls) *videoControlsRenderer {
 	backToZeroButton := renderer.createBackToZeroButton()
 	stepForwardButton := renderer.createStepForwardButton()
 	stepBackwardButton := renderer.createStepBackwardButton()
+	nextFrameButton := renderer.createFrameStepButton(1)
+	previousFrameButton := renderer.createFrameStepButton(-1)
 	volumeMuteButton := renderer.createVolumeMuteButton()
+	levelMeter := visualizer.NewLevelMeter()
+	speedSelect := renderer.createSpeedSelect()
+	audioTrackButton := renderer.createAudioTrackButton()
+	subtitleTrackButton := renderer.createSubtitleTrackButton()
 
 	videoControlsButton := widget.NewButtonWithIcon("", theme.SettingsIcon(), func() {
 		renderer.showVideoControls().Show()
@@ -125,10 +171,16 @@ func newVideoControlsRenderer(parent *VideoControls) *videoControlsRenderer {
 			container.NewHBox( // the controls
 				backToZeroButton,
 				stepBackwardButton,
+				previousFrameButton,
 				playbutton,
+				nextFrameButton,
 				stepForwardButton,
 				fullscreenButton,
+				speedSelect,
+				audioTrackButton,
+				subtitleTrackButton,
 				volumeSlider,
+				levelMeter,
 				volumeMuteButton,
 				videoControlsButton,
 			),
@@ -147,22 +199,34 @@ func newVideoControlsRenderer(parent *VideoControls) *videoControlsRenderer {
 	background := canvas.NewRectangle(color.NRGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: uint8(alpha)})
 	background.CornerRadius = theme.InputRadiusSize() * 4
 
+	renderer.createPreview()
+
 	// register the controls elements
 	renderer.controls = controls
 	renderer.playbutton = playbutton
+	renderer.nextFrameButton = nextFrameButton
+	renderer.previousFrameButton = previousFrameButton
 	renderer.fullscreenButton = fullscreenButton
 	renderer.timeText = timeText
 	renderer.cursor = cursor
 	renderer.background = background
 	renderer.controls = controls
 	renderer.muteButton = volumeMuteButton
+	renderer.levelMeter = levelMeter
+	renderer.audioTrackButton = audioTrackButton
+	renderer.subtitleTrackButton = subtitleTrackButton
+	renderer.speedSelect = speedSelect
 	renderer.videoControlsButton = videoControlsButton
+	renderer.applyFrameButtonsVisibility()
+	renderer.applyLevelMeterVisibility()
 
 	return renderer
 }
 
 // Destroy is an internal function from the fyne.WidgetRenderer interface.
-func (v *videoControlsRenderer) Destroy() { /* no op */ }
+func (v *videoControlsRenderer) Destroy() {
+	v.previewer.close()
+}
 
 // Layout implements the fyne.WidgetRenderer interface. It places the controls at the bottom of the video widget.
 func (v *videoControlsRenderer) Layout(size fyne.Size) {
@@ -200,7 +264,7 @@ func (v *videoControlsRenderer) MinSize() fyne.Size {
 	var w float32
 	h := v.controls.MinSize().Height + theme.InputRadiusSize()
 	for _, o := range v.Objects() {
-		if o == nil {
+		if o == nil || !o.Visible() {
 			continue
 		}
 		if o.MinSize().Width > w {
@@ -215,6 +279,7 @@ func (v *videoControlsRenderer) Objects() []fyne.CanvasObject {
 	return []fyne.CanvasObject{
 		v.background,
 		v.controls,
+		v.preview,
 	}
 }
 
@@ -224,12 +289,39 @@ func (v *videoControlsRenderer) Refresh() {
 	// convert the time.Duration to a string using TimeFormat
 	currentTime := time.Time{}.Add(v.currentTime).Format(streamer.TimeFormat)
 	totalTime := time.Time{}.Add(v.totalTime).Format(streamer.TimeFormat)
-	v.timeText.SetText(fmt.Sprintf("%s / %s", currentTime, totalTime))
+	if v.parent.viewer.IsLive() {
+		// there is no end to reach
+		v.timeText.SetText(currentTime)
+		v.cursor.Hide()
+	} else {
+		v.timeText.SetText(fmt.Sprintf("%s / %s", currentTime, totalTime))
+		v.cursor.Show()
+	}
 	v.manualSeeked = false
 	v.cursor.Max = float64(v.totalTime.Milliseconds())
+	v.cursor.setMarks(v.chapterMarks())
 	v.cursor.SetValue(float64(v.currentTime.Milliseconds()))
 	v.manualSeeked = true
 
+	// set the field, not SetSelected(), to not call the OnChanged callback
+	if rate := playbackRateLabel(v.parent.viewer.PlaybackRate()); v.speedSelect.Selected != rate {
+		v.speedSelect.Selected = rate
+		v.speedSelect.Refresh()
+	}
+
+	// the track menu is only useful with several tracks
+	if v.parent.viewer.audioTrackCount() > 1 {
+		v.audioTrackButton.Show()
+	} else {
+		v.audioTrackButton.Hide()
+	}
+	// the subtitles can be disabled, so the menu is useful with one track
+	if v.parent.viewer.subtitleTrackCount() > 0 {
+		v.subtitleTrackButton.Show()
+	} else {
+		v.subtitleTrackButton.Hide()
+	}
+
 	go time.AfterFunc(100*time.Millisecond, func() { // TODO: we need to wait for the state to be updated
 		if v.parent.viewer.IsPlaying() {
 			v.playbutton.SetIcon(theme.MediaPauseIcon())
@@ -242,8 +334,22 @@ func (v *videoControlsRenderer) Refresh() {
 	v.background.Refresh()
 }
 
-func (v *videoControlsRenderer) cratePositionCursor() *widget.Slider {
-	cursor := widget.NewSlider(0, 100)
+// chapterMarks returns the start of the chapters in milliseconds, as the values of the cursor. The
+// first chapter usually starts at 0, it has no mark.
+func (v *videoControlsRenderer) chapterMarks() []float64 {
+	var marks []float64
+	for _, chapter := range v.parent.viewer.Chapters() {
+		if chapter.Start > 0 && chapter.Start < v.totalTime {
+			marks = append(marks, float64(chapter.Start.Milliseconds()))
+		}
+	}
+	return marks
+}
+
+func (v *videoControlsRenderer) cratePositionCursor() *positionSlider {
+	cursor := newPositionSlider(0, 100)
+	cursor.onHover = v.showPreview
+	cursor.onHoverEnd = v.hidePreview
 	cursor.OnChanged = func(value float64) {
 		if !v.manualSeeked {
 			return
@@ -261,13 +367,7 @@ func (v *videoControlsRenderer) cratePositionCursor() *widget.Slider {
 		if v.parent.viewer.IsPlaying() {
 			return
 		}
-		im, ret := v.parent.viewer.getCurrentFrame(v.parent.viewer.appSink, true)
-		if ret != gst.FlowOK {
-			log.Println("error getting frame", ret)
-			return
-		}
-		v.parent.viewer.Frame().Image = im
-		v.parent.viewer.Frame().Refresh()
+		v.parent.viewer.refreshLastFrame()
 		if v.parent.onTapped != nil {
 			v.parent.onTapped()
 		}
@@ -276,6 +376,79 @@ func (v *videoControlsRenderer) cratePositionCursor() *widget.Slider {
 	return cursor
 }
 
+// createAudioTrackButton creates the button that opens the menu of the audio tracks.
+func (v *videoControlsRenderer) createAudioTrackButton() *widget.Button {
+	var audioTrackButton *widget.Button
+	audioTrackButton = widget.NewButtonWithIcon("", theme.MediaMusicIcon(), func() {
+		v.showTrackMenu(audioTrackButton, v.audioTrackMenu())
+	})
+	audioTrackButton.Importance = widget.LowImportance
+	audioTrackButton.Hide()
+	return audioTrackButton
+}
+
+// createSubtitleTrackButton creates the button that opens the menu of the subtitle tracks.
+func (v *videoControlsRenderer) createSubtitleTrackButton() *widget.Button {
+	var subtitleTrackButton *widget.Button
+	subtitleTrackButton = widget.NewButton("CC", func() {
+		v.showTrackMenu(subtitleTrackButton, v.subtitleTrackMenu())
+	})
+	subtitleTrackButton.Importance = widget.LowImportance
+	subtitleTrackButton.Hide()
+	return subtitleTrackButton
+}
+
+// showTrackMenu shows the menu of the tracks under the button.
+func (v *videoControlsRenderer) showTrackMenu(button *widget.Button, menu *fyne.Menu) {
+	if len(menu.Items) == 0 {
+		return
+	}
+	driver := fyne.CurrentApp().Driver()
+	pos := driver.AbsolutePositionForObject(button)
+	pos.Y += button.Size().Height
+	widget.ShowPopUpMenuAtPosition(menu, driver.CanvasForObject(button), pos)
+	if v.parent.onTapped != nil {
+		v.parent.onTapped()
+	}
+}
+
+// audioTrackMenu returns the menu to select the audio track, the selected track is checked.
+func (v *videoControlsRenderer) audioTrackMenu() *fyne.Menu {
+	selected := v.parent.viewer.AudioTrack()
+	var items []*fyne.MenuItem
+	for _, track := range v.parent.viewer.AudioTracks() {
+		index := track.Index
+		item := fyne.NewMenuItem(track.String(), func() {
+			if err := v.parent.viewer.SelectAudioTrack(index); err != nil {
+				fyne.LogError("Failed to select the audio track", err)
+			}
+		})
+		item.Checked = index == selected
+		items = append(items, item)
+	}
+	return fyne.NewMenu("Audio", items...)
+}
+
+// subtitleTrackMenu returns the menu to select the subtitle track or to disable the subtitles,
+// the selected entry is checked.
+func (v *videoControlsRenderer) subtitleTrackMenu() *fyne.Menu {
+	selected := v.parent.viewer.SubtitleTrack()
+	off := fyne.NewMenuItem("Off", v.parent.viewer.DisableSubtitles)
+	off.Checked = selected < 0
+	items := []*fyne.MenuItem{off}
+	for _, track := range v.parent.viewer.SubtitleTracks() {
+		index := track.Index
+		item := fyne.NewMenuItem(track.String(), func() {
+			if err := v.parent.viewer.SelectSubtitleTrack(index); err != nil {
+				fyne.LogError("Failed to select the subtitle track", err)
+			}
+		})
+		item.Checked = index == selected
+		items = append(items, item)
+	}
+	return fyne.NewMenu("Subtitles", items...)
+}
+
 func (v *videoControlsRenderer) createBackToZeroButton() *widget.Button {
 	backToZeroButton := widget.NewButtonWithIcon("", theme.MediaSkipPreviousIcon(), func() {
 		v.parent.viewer.Seek(0)
@@ -288,6 +461,50 @@ func (v *videoControlsRenderer) createBackToZeroButton() *widget.Button {
 	return backToZeroButton
 }
 
+// applyFrameButtonsVisibility shows or hides the frame step buttons, following the parent settings.
+func (v *videoControlsRenderer) applyFrameButtonsVisibility() {
+	for _, b := range []*widget.Button{v.nextFrameButton, v.previousFrameButton} {
+		if v.parent.showFrameButtons {
+			b.Show()
+		} else {
+			b.Hide()
+		}
+	}
+}
+
+// applyLevelMeterVisibility shows or hides the level meter, following the parent settings. The meter
+// is fed by the viewer only when it is visible.
+func (v *videoControlsRenderer) applyLevelMeterVisibility() {
+	if v.parent.showLevelMeter {
+		v.parent.viewer.setOnLevelMeter(v.levelMeter.SetLevels)
+		v.levelMeter.Show()
+		return
+	}
+	v.parent.viewer.setOnLevelMeter(nil)
+	v.levelMeter.Hide()
+	v.levelMeter.Reset()
+}
+
+// createFrameStepButton creates a button that steps "frames" frames, forward if
+// "frames" is positive or backward if it is negative.
+func (v *videoControlsRenderer) createFrameStepButton(frames int) *widget.Button {
+	icon := theme.NavigateNextIcon()
+	if frames < 0 {
+		icon = theme.NavigateBackIcon()
+	}
+	frameStepButton := widget.NewButtonWithIcon("", icon, func() {
+		if _, err := v.parent.viewer.StepFrames(frames); err != nil {
+			fyne.LogError("Failed to step frames", err)
+		}
+		if v.parent.onTapped == nil {
+			return
+		}
+		v.parent.onTapped()
+	})
+	frameStepButton.Importance = widget.LowImportance
+	return frameStepButton
+}
+
 func (v *videoControlsRenderer) createFullScreenButton() *widget.Button {
 	fullscreenButton := widget.NewButtonWithIcon("", theme.ViewFullScreenIcon(), func() {
 		v.isFullScreen = !v.isFullScreen
@@ -316,6 +533,62 @@ func (v *videoControlsRenderer) createPlayButton() *widget.Button {
 	return playbutton
 }
 
+// createPreview creates the hidden box that displays the frame and the time of the hovered position.
+func (v *videoControlsRenderer) createPreview() {
+	v.previewImage = canvas.NewImageFromImage(nil)
+	v.previewImage.FillMode = canvas.ImageFillContain
+	v.previewImage.SetMinSize(fyne.NewSize(previewWidth, previewHeight))
+
+	v.previewTime = canvas.NewText("", theme.ForegroundColor())
+	v.previewTime.Alignment = fyne.TextAlignCenter
+
+	background := canvas.NewRectangle(theme.OverlayBackgroundColor())
+	background.CornerRadius = theme.InputRadiusSize()
+
+	v.preview = container.NewStack(
+		background,
+		container.NewPadded(
+			container.NewBorder(nil, v.previewTime, nil, nil, v.previewImage),
+		),
+	)
+	v.preview.Hide()
+
+	v.previewer = newPreviewer(func(pos time.Duration, img image.Image) {
+		if !v.preview.Visible() || pos != v.previewPos {
+			return
+		}
+		v.previewImage.Image = img
+		v.previewImage.Refresh()
+	})
+}
+
+func (v *videoControlsRenderer) createSpeedSelect() *widget.Select {
+	labels := make([]string, len(playbackRates))
+	for i, rate := range playbackRates {
+		labels[i] = playbackRateLabel(rate)
+	}
+	speedSelect := widget.NewSelect(labels, nil)
+	speedSelect.Selected = playbackRateLabel(1)
+	speedSelect.OnChanged = func(value string) {
+		for i, label := range labels {
+			if label != value {
+				continue
+			}
+			if err := v.parent.viewer.SetPlaybackRate(playbackRates[i]); err != nil {
+				fyne.LogError("Failed to change the playback rate", err)
+				speedSelect.Selected = playbackRateLabel(v.parent.viewer.PlaybackRate())
+				speedSelect.Refresh()
+			}
+			break
+		}
+		if v.parent.onTapped == nil {
+			return
+		}
+		v.parent.onTapped()
+	}
+	return speedSelect
+}
+
 func (v *videoControlsRenderer) createStepBackwardButton() *widget.Button {
 	stepBackwardButton := widget.NewButtonWithIcon("", theme.MediaFastRewindIcon(), func() {
 		pos, _ := v.parent.viewer.CurrentPosition()
@@ -372,6 +645,63 @@ func (v *videoControlsRenderer) createVolumeSlider() *widget.Slider {
 	return volumeSlider
 }
 
+// hidePreview hides the preview of the hovered position.
+func (v *videoControlsRenderer) hidePreview() {
+	v.preview.Hide()
+}
+
+// showPreview displays the preview of the position (in milliseconds) above the cursor.
+// x is the pointer position in the cursor.
+func (v *videoControlsRenderer) showPreview(value float64, x float32) {
+	if v.totalTime <= 0 {
+		return
+	}
+
+	pos := time.Duration(value) * time.Millisecond
+	v.previewTime.Text = time.Time{}.Add(pos).Format(streamer.TimeFormat)
+	if chapter, ok := v.parent.viewer.chapterAt(pos); ok && chapter.Title != "" {
+		v.previewTime.Text += " - " + chapter.Title
+	}
+
+	uri := v.parent.viewer.uri
+	img, rounded, ok := v.previewer.frameAt(uri, pos)
+	v.previewPos = rounded
+	if !ok {
+		// custom pipeline or unsupported location, only the time can be displayed
+		v.previewImage.Hide()
+	} else {
+		v.previewImage.Show()
+		if img != nil {
+			v.previewImage.Image = img
+		}
+	}
+
+	// place the preview above the controls, centered on the pointer
+	driver := fyne.CurrentApp().Driver()
+	offset := driver.AbsolutePositionForObject(v.cursor).Subtract(driver.AbsolutePositionForObject(v.parent))
+	size := v.preview.MinSize()
+	left := offset.X + x - size.Width/2
+	if maxLeft := v.parent.Size().Width - size.Width; left > maxLeft {
+		left = maxLeft
+	}
+	if left < 0 {
+		left = 0
+	}
+	v.preview.Resize(size)
+	v.preview.Move(fyne.NewPos(left, v.controls.Position().Y-size.Height-theme.Padding()))
+	v.preview.Show()
+	v.preview.Refresh()
+
+	if v.parent.onTapped != nil {
+		v.parent.onTapped()
+	}
+}
+
+// playbackRateLabel returns the label of the rate in the speed selector, e.g. "1.5x".
+func playbackRateLabel(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64) + "x"
+}
+
 // showVideoControls displays the video controls dialog to control the video balance (contrast, brightness, hue and saturation).
 func (v *videoControlsRenderer) showVideoControls() dialog.Dialog {
 	if v.parent.viewer.pipeline == nil {
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -89,3 +91,97 @@ func TestShowVideoControls(t *testing.T) {
 	}
 
 }
+
+func TestFrameStepButtons(t *testing.T) {
+	setup(t)
+
+	widget := NewPlayer()
+	window := test.NewWindow(widget)
+	window.Resize(fyne.NewSize(800, 600))
+
+	controls := widget.controls
+	assert.False(t, controls.renderer.nextFrameButton.Visible())
+	assert.False(t, controls.renderer.previousFrameButton.Visible())
+
+	widget.SetFrameStepButtonsVisible(true)
+	assert.True(t, controls.renderer.nextFrameButton.Visible())
+	assert.True(t, controls.renderer.previousFrameButton.Visible())
+}
+
+func TestLevelMeter(t *testing.T) {
+	setup(t)
+
+	widget := NewPlayer()
+	window := test.NewWindow(widget)
+	window.Resize(fyne.NewSize(800, 600))
+
+	renderer := widget.controls.renderer
+	assert.False(t, renderer.levelMeter.Visible())
+	assert.Nil(t, widget.onLevelMeter)
+
+	// the function of the user is kept
+	widget.SetOnAudioLevel(func(rms, peak []float64) {})
+	widget.SetLevelMeterVisible(true)
+	assert.True(t, renderer.levelMeter.Visible())
+	assert.NotNil(t, widget.onLevelMeter)
+	assert.NotNil(t, widget.onAudioLevel)
+
+	widget.SetLevelMeterVisible(false)
+	assert.False(t, renderer.levelMeter.Visible())
+	assert.Nil(t, widget.onLevelMeter)
+	assert.NotNil(t, widget.onAudioLevel)
+}
+
+func TestPositionPreview(t *testing.T) {
+	setup(t)
+
+	player := NewPlayer()
+	window := test.NewWindow(player)
+	window.Resize(fyne.NewSize(800, 600))
+	window.Show()
+
+	err := player.Open(storage.NewFileURI(_testVideoFile))
+	assert.Nil(t, err)
+	player.Pause()
+	time.Sleep(500 * time.Millisecond)
+
+	renderer := player.controls.renderer
+	assert.False(t, renderer.preview.Visible())
+
+	cursorSize := renderer.cursor.Size()
+	renderer.cursor.MouseIn(&desktop.MouseEvent{
+		PointEvent: fyne.PointEvent{Position: fyne.NewPos(cursorSize.Width/2, cursorSize.Height/2)},
+	})
+	assert.True(t, renderer.preview.Visible())
+	assert.NotEmpty(t, renderer.previewTime.Text)
+
+	// the frame is grabbed in a secondary pipeline
+	assert.Eventually(t, func() bool {
+		return renderer.previewImage.Image != nil
+	}, 5*time.Second, 100*time.Millisecond)
+
+	renderer.cursor.MouseOut()
+	assert.False(t, renderer.preview.Visible())
+}
+
+func TestPreviewUnsupportedLocation(t *testing.T) {
+	setup(t)
+
+	previewer := newPreviewer(nil)
+	defer previewer.close()
+
+	// the location of a custom opener can't be read by the preview pipeline
+	u, _ := storage.ParseURI("preview://pattern")
+	for i := 0; i < 3; i++ {
+		img, _, ok := previewer.frameAt(u, time.Duration(i)*time.Second)
+		assert.Nil(t, img)
+		assert.False(t, ok)
+	}
+	assert.False(t, previewer.busy)
+	assert.Nil(t, previewer.grabber)
+	assert.NotContains(t, logBuffer.String(), "preview")
+
+	// a supported location enables the preview again
+	_, _, ok := previewer.frameAt(storage.NewFileURI(_testVideoFile), 0)
+	assert.True(t, ok)
+}
@@ -21,11 +21,13 @@ var _ fyne.DoubleTappable = (*Player)(nil)
 // and many others features.
 type Player struct {
 	*Viewer
-	autoHideTimer   time.Duration // time to wait before hiding the controls
-	autoHide        bool
-	cancelAutoHide  context.CancelFunc // cancel the autoHide goroutine
-	autoHideContext context.Context    // context of the autoHide goroutine
-	controls        *VideoControls     // controls of the video widget
+	autoHideTimer    time.Duration // time to wait before hiding the controls
+	autoHide         bool
+	cancelAutoHide   context.CancelFunc // cancel the autoHide goroutine
+	autoHideContext  context.Context    // context of the autoHide goroutine
+	controls         *VideoControls     // controls of the video widget
+	showFrameButtons bool               // show the frame step buttons in the controls
+	showLevelMeter   bool               // show the audio level meter in the controls
 }
 
 // NewPlayer returns a new video widget with controls and interaction.
@@ -67,9 +69,12 @@ func NewPlayer() *Player {
 // Implements: fyne.Widget
 func (v *Player) CreateRenderer() fyne.WidgetRenderer {
 	v.controls = NewVideoControls(v.Viewer)
+	v.controls.SetFrameStepButtonsVisible(v.showFrameButtons)
+	v.controls.SetLevelMeterVisible(v.showLevelMeter)
 	return widget.NewSimpleRenderer(
 		container.NewStack(
 			v.Frame(),
+			v.subtitleOverlay,
 			v.controls,
 		),
 	)
@@ -124,6 +129,24 @@ func (v *Player) SetAutoHideTimer(d time.Duration) {
 	v.autoHideTimer = d
 }
 
+// SetFrameStepButtonsVisible shows or hides the buttons to go to the next or previous frame
+// in the controls. They are hidden by default.
+func (v *Player) SetFrameStepButtonsVisible(visible bool) {
+	v.showFrameButtons = visible
+	if v.controls != nil {
+		v.controls.SetFrameStepButtonsVisible(visible)
+	}
+}
+
+// SetLevelMeterVisible shows or hides the audio level meter beside the volume slider of the controls.
+// It is hidden by default. See VideoControls.SetLevelMeterVisible.
+func (v *Player) SetLevelMeterVisible(visible bool) {
+	v.showLevelMeter = visible
+	if v.controls != nil {
+		v.controls.SetLevelMeterVisible(visible)
+	}
+}
+
 // Tapped hides the controls of the video widget.
 //
 // Implements: fyne.Tappable
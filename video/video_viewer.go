@@ -2,22 +2,30 @@ package video
 
 import (
 	"fmt"
+	"io"
+	"math"
+	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
 	"github.com/go-gst/go-gst/gst"
 	gstApp "github.com/go-gst/go-gst/gst/app"
 	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/internal/subtitles"
 	"github.com/metal3d/fyne-streamer/internal/utils"
+	"github.com/metal3d/fyne-streamer/playlist"
 )
 
 var _ fyne.Widget = (*Viewer)(nil)
+var _ utils.MediaAudioLevel = (*Viewer)(nil)
 var _ utils.MediaControl = (*Viewer)(nil)
 var _ utils.MediaDuration = (*Viewer)(nil)
 var _ utils.MediaOpener = (*Viewer)(nil)
 var _ utils.MediaSeeker = (*Viewer)(nil)
+var _ utils.MediaSpectrum = (*Viewer)(nil)
 
 // Viewer widget is a simple video player with no controls to display.
 // This is a base widget to only read a video or that can be extended to create a video player with controls.
@@ -31,10 +39,48 @@ type Viewer struct {
 	onPaused         func()
 	onStartPlaying   func()
 	onTitle          func(string)
+	onSpectrum       func([]float64)
+	onAudioLevel     func(rms, peak []float64)
+	onLevelMeter     func(rms, peak []float64) // the level meter of the controls, beside onAudioLevel
+	onChapters       func([]Chapter)
+	onMetadata       func(streamer.Metadata)
+	spectrumBands    int           // number of bands of the spectrum, 0 for the element default
+	spectrumInterval time.Duration // interval between two spectrum messages, 0 for the element default
 	rate             int
 	imageQuality     int
 	width            int
 	height           int
+	frameFormat      string          // raw format negotiated by the appsink, empty for encoded images
+	framePool        *framePool      // reused images for raw frames
+	frameRate        float64         // frames per second, 0 if unknown or variable
+	playbackRate     float64         // speed of the playback, negative to play backward
+	frameLock        sync.Mutex      // protects the displayed image
+	uri              fyne.URI        // the opened location, nil for custom pipelines, readers and storage locations
+	reader           io.Closer       // reader of the storage location, closed by reset
+	audioTracks      []*gst.Pad      // pads of the audio selector, one per audio track
+	audioTrack       int             // index of the selected audio track
+	subtitleTracks   []*gst.Pad      // pads of the subtitle selector, one per text subtitle track
+	subtitleTrack    int             // index of the displayed subtitle track, -1 if disabled
+	trackLock        sync.Mutex      // protects the tracks that are linked while prerolling
+	subtitleCues     []subtitles.Cue // external subtitles
+	embeddedCues     []subtitles.Cue // last received cues of the embedded subtitle track
+	subtitleOffset   time.Duration
+	subtitleLock     sync.Mutex // protects the cues that are read by the streaming thread
+	subtitleOverlay  *subtitleOverlay
+	chapters         []Chapter
+	chapterLock      sync.Mutex // protects the chapters that are set by the bus watch
+	metadata         streamer.Metadata
+	metadataLock     sync.Mutex         // protects the metadata that are set by the bus watch
+	playlist         *playlist.Playlist // advanced at the end of the stream, nil if there is none
+	live             bool               // the source is live, it can't be seeked and has no duration
+	liveLock         sync.Mutex         // protects live, that is read by the controls
+	variants         []Variant          // variants of the adaptive stream
+	variant          int                // index of the selected variant, -1 for the automatic selection
+	variantFetch     int                // incremented when the media changes, see fetchVariants
+	variantLock      sync.Mutex         // protects the variants that are applied when prerolling
+	onVariants       func([]Variant)    // protected by variantLock, it is called by fetchVariants
+	recording        *recording         // nil if the stream is not recorded
+	recordingLock    sync.Mutex         // protects the recording that is stopped when the media changes
 	duration         time.Duration
 	frame            *canvas.Image
 	fullscreenWindow fyne.Window
@@ -74,7 +120,7 @@ func (v *Viewer) Clear() error {
 //
 // Implements: fyne.Widget
 func (v *Viewer) CreateRenderer() fyne.WidgetRenderer {
-	return widget.NewSimpleRenderer(v.frame)
+	return widget.NewSimpleRenderer(container.NewStack(v.frame, v.subtitleOverlay))
 }
 
 // CurrentPosition returns the current position of the stream in time.
@@ -94,6 +140,9 @@ func (v *Viewer) Duration() (time.Duration, error) {
 	if v.pipeline == nil {
 		return 0, streamer.ErrNoPipeline
 	}
+	if v.IsLive() {
+		return 0, streamer.ErrNoDuration
+	}
 	if v.duration != 0 {
 		return v.duration, nil
 	}
@@ -120,6 +169,12 @@ func (v *Viewer) Frame() *canvas.Image {
 	return v.frame
 }
 
+// FrameRate returns the number of frames per second of the video, read from the
+// negotiated caps. It returns 0 if the video is not prerolled or if the framerate is variable.
+func (v *Viewer) FrameRate() float64 {
+	return v.frameRate
+}
+
 // GetBrightness returns the brightness of the video.
 func (v *Viewer) GetBrightness() float64 {
 	if v.pipeline == nil {
@@ -216,6 +271,22 @@ func (v *Viewer) IsMuted() bool {
 	return isMuted.(bool)
 }
 
+// Metadata returns the metadata found in the tags of the media so far. The tags are found while
+// prerolling and playing, use SetOnMetadata to be notified.
+func (v *Viewer) Metadata() streamer.Metadata {
+	v.metadataLock.Lock()
+	defer v.metadataLock.Unlock()
+	return v.metadata
+}
+
+// IsLive returns true if the stream is live, e.g. a RTSP camera. A live stream can't be seeked and
+// has no duration.
+func (v *Viewer) IsLive() bool {
+	v.liveLock.Lock()
+	defer v.liveLock.Unlock()
+	return v.live
+}
+
 // IsPlaying returns true if the pipeline is in the playing state.
 func (v *Viewer) IsPlaying() bool {
 	if v.pipeline == nil {
@@ -261,6 +332,12 @@ func (v *Viewer) Pause() error {
 	return nil
 }
 
+// PlaybackRate returns the current playback rate. 1 is the normal speed, and a negative rate
+// means that the stream is played backward.
+func (v *Viewer) PlaybackRate() float64 {
+	return v.playbackRate
+}
+
 // Pipeline returns the gstreamer pipeline.
 func (v *Viewer) Pipeline() *gst.Pipeline {
 	return v.pipeline
@@ -281,19 +358,27 @@ func (v *Viewer) Play() error {
 	return err
 }
 
+// Playlist returns the attached playlist, nil if there is none.
+func (v *Viewer) Playlist() *playlist.Playlist {
+	return v.playlist
+}
+
 // Seek the position to "pos" Nanoseconds. Set the playing stream to this time position.
 // If the element or the pipeline cannot be seekable, the operation is cancelled.
 func (v *Viewer) Seek(pos time.Duration) error {
 	if v.pipeline == nil {
 		return streamer.ErrNoPipeline
 	}
+	if v.IsLive() {
+		return streamer.ErrSeekUnsupported
+	}
 	query := gst.NewSeekingQuery(gst.FormatTime)
 	if !v.pipeline.Query(query) {
 		return streamer.ErrSeekUnsupported
 	}
 
 	defer v.resync()
-	done := v.pipeline.SeekTime(pos, gst.SeekFlagFlush)
+	done := v.seek(pos, gst.SeekFlagFlush)
 	if !done {
 		return streamer.ErrSeekFailed
 	}
@@ -402,11 +487,31 @@ func (v *Viewer) SetMinSize(size fyne.Size) {
 	v.Frame().SetMinSize(size)
 }
 
+// SetOnAudioLevel set the function that is called with the RMS and peak levels of each audio channel, in dB.
+// The pipeline must have a "level" element named with LevelElementName, which is the case of the
+// default pipelines.
+func (v *Viewer) SetOnAudioLevel(f func(rms, peak []float64)) {
+	v.onAudioLevel = f
+	v.postAudioMessages()
+}
+
+// SetOnChaptersChanged set the function that is called when the chapters of the media are found or
+// updated, see Chapters.
+func (v *Viewer) SetOnChaptersChanged(f func([]Chapter)) {
+	v.onChapters = f
+}
+
 // SetOnEOS set the function to call when EOS is reached in the pipeline. E.g. when the// video ends.
 func (v *Viewer) SetOnEOS(f func()) {
 	v.onEOS = f
 }
 
+// SetOnMetadata set the function that is called when tags of the media are found, with all the
+// metadata found so far. See Metadata.
+func (v *Viewer) SetOnMetadata(f func(streamer.Metadata)) {
+	v.onMetadata = f
+}
+
 // SetOnNewFrame set the function that is called when a new frame is available and presented to the view. The position is set as time.Duration to the function.
 func (v *Viewer) SetOnNewFrame(f func(time.Duration)) {
 	v.onNewFrame = f
@@ -422,11 +527,68 @@ func (v *Viewer) SetOnPreRoll(f func()) {
 	v.onPreRoll = f
 }
 
+// SetOnSpectrum set the function that is called with the magnitudes of the audio frequency bands, in dB,
+// from the lowest to the highest frequency. The pipeline must have a "spectrum" element named with
+// SpectrumElementName, which is the case of the default pipelines.
+func (v *Viewer) SetOnSpectrum(f func([]float64)) {
+	v.onSpectrum = f
+	v.postAudioMessages()
+}
+
 func (v *Viewer) SetOnTitle(f func(string)) {
 	v.onTitle = f
 }
 
+// SetOnVariantsChanged set the function that is called when the variants of the adaptive stream
+// are read from its manifest, in another goroutine. See Variants.
+func (v *Viewer) SetOnVariantsChanged(f func([]Variant)) {
+	v.variantLock.Lock()
+	defer v.variantLock.Unlock()
+	v.onVariants = f
+}
+
+// SetPlaybackRate changes the speed of the playback. The absolute value of the rate must be
+// between MinPlaybackRate and MaxPlaybackRate. A negative rate plays the stream backward, if the
+// demuxer allows it.
+//
+// The default pipelines use a "scaletempo" element to keep the pitch of the audio.
+func (v *Viewer) SetPlaybackRate(rate float64) error {
+	if math.Abs(rate) < streamer.MinPlaybackRate || math.Abs(rate) > streamer.MaxPlaybackRate {
+		return fmt.Errorf("%w: %v", streamer.ErrInvalidPlaybackRate, rate)
+	}
+	if v.pipeline == nil {
+		return streamer.ErrNoPipeline
+	}
+	pos, err := v.CurrentPosition()
+	if err != nil {
+		return err
+	}
+
+	defer v.resync()
+	previous := v.playbackRate
+	v.playbackRate = rate
+	if !v.seek(pos, gst.SeekFlagFlush|gst.SeekFlagAccurate) {
+		v.playbackRate = previous
+		return fmt.Errorf("%w: the rate %v is not supported by the stream", streamer.ErrSeekFailed, rate)
+	}
+	return nil
+}
+
+// SetPlaylist attaches the viewer to the playlist: the entries selected in the playlist are opened
+// in the viewer, and the next entry is played at the end of the stream instead of going back to the
+// beginning. Use nil to detach the playlist.
+func (v *Viewer) SetPlaylist(p *playlist.Playlist) {
+	if v.playlist != nil && v.playlist != p {
+		v.playlist.Detach(v)
+	}
+	v.playlist = p
+	if p != nil {
+		p.Attach(v)
+	}
+}
+
 // SetQuality of the jpeg encoder. If que quality is not between 0 and 100, nothing is done.
+// It only applies to pipelines using an image encoder (legacy mode), raw frames are not compressed.
 func (v *Viewer) SetQuality(q int) error {
 	if v.pipeline == nil {
 		return streamer.ErrNoPipeline
@@ -483,6 +645,32 @@ func (v *Viewer) SetScaleMode(mode canvas.ImageScale) {
 	v.frame.ScaleMode = mode
 }
 
+// SetSpectrumBands sets the number of frequency bands of the spectrum. The setting is kept
+// for the next opened pipelines.
+func (v *Viewer) SetSpectrumBands(bands int) error {
+	if bands <= 0 {
+		return fmt.Errorf("%w: %d bands", streamer.ErrInvalidSpectrum, bands)
+	}
+	v.spectrumBands = bands
+	if v.pipeline == nil {
+		return nil
+	}
+	return utils.ConfigureSpectrum(v.pipeline, bands, 0)
+}
+
+// SetSpectrumInterval sets the interval between two spectrum updates. The setting is kept
+// for the next opened pipelines.
+func (v *Viewer) SetSpectrumInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: interval %v", streamer.ErrInvalidSpectrum, interval)
+	}
+	v.spectrumInterval = interval
+	if v.pipeline == nil {
+		return nil
+	}
+	return utils.ConfigureSpectrum(v.pipeline, 0, interval)
+}
+
 // SetState sets the state of the pipeline to the given state.
 func (v *Viewer) SetState(state gst.State) error {
 	defer v.resync()
@@ -505,6 +693,59 @@ func (v *Viewer) SetVolume(volume float64) {
 	volumeElement.SetProperty("volume", volume)
 }
 
+// StepFrames moves the video of n frames, forward if n is positive or backward if n is negative.
+// The pipeline is paused if needed, and the new position is returned.
+//
+// Forward steps use GStreamer step events. Step events cannot go backward without reverse
+// playback, so backward steps are made with an accurate seek based on the frame rate.
+func (v *Viewer) StepFrames(n int) (time.Duration, error) {
+	if v.pipeline == nil {
+		return 0, streamer.ErrNoPipeline
+	}
+
+	if v.IsPlaying() {
+		if err := v.Pause(); err != nil {
+			return 0, err
+		}
+	}
+
+	switch {
+	case n > 0:
+		event := gst.NewStepEvent(formatBuffers, uint64(n), 1, true, false)
+		if !v.appSink.Element.SendEvent(event) {
+			return 0, streamer.ErrStepFailed
+		}
+	case n < 0:
+		if v.frameRate <= 0 {
+			return 0, streamer.ErrNoFrameRate
+		}
+		pos, err := v.CurrentPosition()
+		if err != nil {
+			return 0, err
+		}
+		pos += time.Duration(float64(n) * float64(time.Second) / v.frameRate)
+		if pos < 0 {
+			pos = 0
+		}
+		if !v.seek(pos, gst.SeekFlagFlush|gst.SeekFlagAccurate) {
+			return 0, streamer.ErrSeekFailed
+		}
+	}
+
+	// wait for the new frame to be prerolled, then display it
+	v.pipeline.GetState(gst.StatePaused, gst.ClockTime(time.Second))
+	v.refreshLastFrame()
+
+	pos, err := v.CurrentPosition()
+	if err != nil {
+		return 0, err
+	}
+	if v.onNewFrame != nil {
+		go v.onNewFrame(pos)
+	}
+	return pos, nil
+}
+
 // Stop the stream if the pipeline is not nil.
 func (v *Viewer) Stop() error {
 	if v.pipeline == nil {
@@ -546,11 +787,16 @@ func (v *Viewer) VideoSize() fyne.Size {
 func CreateBaseVideoViewer() *Viewer {
 	utils.GstreamerInit()
 	v := &Viewer{
-		frame:        canvas.NewImageFromResource(nil),
-		rate:         30,
-		imageQuality: 85,
+		frame:         canvas.NewImageFromResource(nil),
+		framePool:     newFramePool(),
+		playbackRate:  1,
+		rate:          defaultMaxRate,
+		imageQuality:  85,
+		subtitleTrack: -1,
+		variant:       -1,
 	}
 
+	v.subtitleOverlay = newSubtitleOverlay(v)
 	v.SetFillMode(canvas.ImageFillContain)
 	v.SetScaleMode(canvas.ImageScaleFastest)
 	return v
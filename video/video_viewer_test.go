@@ -2,6 +2,11 @@ package video
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -9,6 +14,8 @@ import (
 	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/test"
 	"github.com/go-gst/go-gst/gst"
+	streamer "github.com/metal3d/fyne-streamer"
+	"github.com/metal3d/fyne-streamer/playlist"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -200,3 +207,307 @@ func TestSeek(t *testing.T) {
 	err = video.Seek(2 * time.Second)
 	assert.Nil(t, err, "error while seeking position")
 }
+
+func TestRawFrames(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	err := video.SetPipelineFromString(`
+    videotestsrc name={{.InputElementName}} !
+    videoconvert !
+    videoscale !
+    video/x-raw,width=320,height=240 !
+    videorate name={{.VideoRateElementName}} !
+    videoconvert !
+    video/x-raw,format=RGBA !
+    appsink name={{ .AppSinkElementName }}`)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	video.SetOnNewFrame(func(at time.Duration) {
+		cancel()
+	})
+
+	err = video.Play()
+	assert.Nil(t, err)
+	<-ctx.Done()
+	video.Pause()
+
+	img, ok := video.Frame().Image.(*image.NRGBA)
+	assert.True(t, ok, "frame should be a raw NRGBA image, got %T", video.Frame().Image)
+	if ok {
+		assert.Equal(t, image.Rect(0, 0, 320, 240), img.Bounds())
+	}
+}
+
+// BenchmarkFramePool compares the allocations of raw frames with and without
+// the frame pool, on a videotestsrc pipeline.
+func BenchmarkFramePool(b *testing.B) {
+	b.Run("pool", func(b *testing.B) {
+		benchmarkRawFrames(b, true)
+	})
+	b.Run("nopool", func(b *testing.B) {
+		benchmarkRawFrames(b, false)
+	})
+}
+
+func benchmarkRawFrames(b *testing.B, pooled bool) {
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	if !pooled {
+		video.framePool = nil
+	}
+	err := video.SetPipelineFromString(fmt.Sprintf(`
+    videotestsrc name={{.InputElementName}} num-buffers=%d !
+    video/x-raw,width=1280,height=720 !
+    videoconvert !
+    video/x-raw,format=RGBA !
+    appsink name={{ .AppSinkElementName }} sync=false`, b.N))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer video.Stop()
+
+	done := make(chan struct{})
+	var once sync.Once
+	video.SetOnEOS(func() {
+		once.Do(func() { close(done) })
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	if err := video.Play(); err != nil {
+		b.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Minute):
+		b.Fatal("timeout waiting for EOS")
+	}
+	b.StopTimer()
+}
+
+func TestStepFrames(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	err := video.Open(storage.NewFileURI(_testVideoFile))
+	assert.Nil(t, err)
+
+	err = video.Pause()
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	assert.True(t, video.FrameRate() > 0, "the frame rate should be known after preroll")
+
+	pos, err := video.StepFrames(5)
+	assert.Nil(t, err)
+	assert.True(t, pos > 0, "position should move forward, got %v", pos)
+	assert.False(t, video.IsPlaying())
+
+	back, err := video.StepFrames(-2)
+	assert.Nil(t, err)
+	assert.True(t, back < pos, "position should move backward, got %v after %v", back, pos)
+}
+
+func TestPlaybackRate(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	assert.Equal(t, float64(1), video.PlaybackRate())
+
+	err := video.Open(storage.NewFileURI(_testVideoFile))
+	assert.Nil(t, err)
+
+	err = video.SetPlaybackRate(10)
+	assert.True(t, errors.Is(err, streamer.ErrInvalidPlaybackRate))
+
+	err = video.Play()
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+
+	err = video.SetPlaybackRate(2)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(2), video.PlaybackRate())
+
+	before, _ := video.CurrentPosition()
+	time.Sleep(500 * time.Millisecond)
+	after, _ := video.CurrentPosition()
+	assert.True(t, after-before > 500*time.Millisecond, "position should move faster than the clock, %v -> %v", before, after)
+
+	// the rate is kept after a seek
+	err = video.Seek(time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(2), video.PlaybackRate())
+}
+
+func TestSetPlaylist(t *testing.T) {
+	setup(t)
+
+	video := NewViewer()
+	first := playlist.New(storage.NewFileURI(_testVideoFile))
+	second := playlist.New(storage.NewFileURI(_testVideoFile))
+	video.SetPlaylist(first)
+	assert.Equal(t, first, video.Playlist())
+
+	// the replaced and the removed playlists don't drive the viewer anymore
+	video.SetPlaylist(second)
+	assert.True(t, errors.Is(first.SetCurrent(0), streamer.ErrNoPlayer))
+	video.SetPlaylist(nil)
+	assert.True(t, errors.Is(second.SetCurrent(0), streamer.ErrNoPlayer))
+	assert.Nil(t, video.Playlist())
+}
+
+func TestPostAudioMessages(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	err := video.Open(storage.NewFileURI(_testVideoFile))
+	assert.Nil(t, err)
+
+	posted := func(name streamer.ElementName) interface{} {
+		element, err := video.pipeline.GetElementByName(name)
+		assert.Nil(t, err)
+		value, err := element.GetProperty("post-messages")
+		assert.Nil(t, err)
+		return value
+	}
+	// nothing listens to the audio elements
+	assert.Equal(t, false, posted(streamer.SpectrumElementName))
+	assert.Equal(t, false, posted(streamer.LevelElementName))
+
+	video.SetOnSpectrum(func([]float64) {})
+	assert.Equal(t, true, posted(streamer.SpectrumElementName))
+	assert.Equal(t, false, posted(streamer.LevelElementName))
+	video.SetOnSpectrum(nil)
+	assert.Equal(t, false, posted(streamer.SpectrumElementName))
+
+	// the callbacks are kept for the next media
+	video.SetOnAudioLevel(func(rms, peak []float64) {})
+	err = video.Open(storage.NewFileURI(_testVideoFile))
+	assert.Nil(t, err)
+	assert.Equal(t, true, posted(streamer.LevelElementName))
+}
+
+func TestReversePlaybackRewind(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+	var eos atomic.Int32
+	video.SetOnEOS(func() { eos.Add(1) })
+
+	err := video.Open(storage.NewFileURI(_testVideoFile))
+	assert.Nil(t, err)
+	err = video.Play()
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	err = video.Seek(time.Second)
+	assert.Nil(t, err)
+	err = video.SetPlaybackRate(-2)
+	assert.Nil(t, err)
+
+	// the stream reaches its beginning, and is rewound to its end
+	time.Sleep(2 * time.Second)
+	assert.Equal(t, int32(1), eos.Load())
+	assert.Equal(t, float64(-2), video.PlaybackRate())
+
+	// it is played backward again, instead of ending at once
+	err = video.Play()
+	assert.Nil(t, err)
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, int32(1), eos.Load())
+	duration, err := video.Duration()
+	assert.Nil(t, err)
+	pos, err := video.CurrentPosition()
+	assert.Nil(t, err)
+	assert.True(t, pos > 0 && pos < duration, "position should move backward from the end, got %v", pos)
+	video.Stop()
+}
+
+func TestAudioTracks(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+
+	// a stream with a mono and a stereo audio tracks
+	err := video.SetPipelineFromString(`
+    videotestsrc num-buffers=100 ! video/x-raw,width=160,height=120 !
+    videoconvert ! video/x-raw,format=RGBA !
+    appsink name={{ .AppSinkElementName }}
+
+    audiotestsrc num-buffers=100 ! audio/x-raw,channels=1 ! mux.
+    audiotestsrc num-buffers=100 ! audio/x-raw,channels=2 ! mux.
+    matroskamux name=mux streamable=true !
+    decodebin name={{ .DecodeElementName }}
+
+    input-selector name={{ .AudioSelectorElementName }} ! fakesink`)
+	assert.Nil(t, err)
+	assert.Equal(t, -1, video.AudioTrack())
+
+	err = video.Pause()
+	assert.Nil(t, err)
+	time.Sleep(time.Second)
+
+	tracks := video.AudioTracks()
+	assert.Len(t, tracks, 2)
+	channels := []int{}
+	for _, track := range tracks {
+		channels = append(channels, track.Channels)
+	}
+	assert.ElementsMatch(t, []int{1, 2}, channels)
+	assert.Equal(t, 0, video.AudioTrack())
+
+	err = video.SelectAudioTrack(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, video.AudioTrack())
+
+	err = video.SelectAudioTrack(2)
+	assert.True(t, errors.Is(err, streamer.ErrInvalidTrack))
+}
+
+func TestMetadata(t *testing.T) {
+	setup(t)
+	video := NewViewer()
+	_ = test.WidgetRenderer(video)
+
+	err := video.SetPipelineFromString(`
+    videotestsrc num-buffers=10 ! video/x-raw,width=160,height=120 !
+    videoconvert ! video/x-raw,format=RGBA !
+    appsink name={{ .AppSinkElementName }}`)
+	assert.Nil(t, err)
+
+	var title string
+	var metadata streamer.Metadata
+	video.SetOnTitle(func(t string) { title = t })
+	video.SetOnMetadata(func(m streamer.Metadata) { metadata = m })
+
+	// the tags of two streams are accumulated
+	tags := gst.NewEmptyTagList()
+	tags.AddValue(gst.TagMergeReplace, gst.TagTitle, "Big Buck Bunny")
+	tags.AddValue(gst.TagMergeReplace, gst.TagVideoCodec, "H.264")
+	tags.AddValue(gst.TagMergeReplace, gst.TagBitrate, uint(2000000))
+	video.handleTagMessage(gst.NewTagMessage(video.pipeline, tags))
+
+	tags = gst.NewEmptyTagList()
+	tags.AddValue(gst.TagMergeReplace, gst.TagArtist, "Blender Foundation")
+	tags.AddValue(gst.TagMergeReplace, gst.TagAudioCodec, "Vorbis")
+	tags.AddValue(gst.TagMergeReplace, gst.TagBitrate, uint(128000))
+	video.handleTagMessage(gst.NewTagMessage(video.pipeline, tags))
+
+	assert.Equal(t, "Big Buck Bunny", title)
+	assert.Equal(t, metadata, video.Metadata())
+	assert.Equal(t, "Big Buck Bunny", metadata.Title)
+	assert.Equal(t, "Blender Foundation", metadata.Artist)
+	assert.Equal(t, "H.264", metadata.VideoCodec)
+	assert.Equal(t, "Vorbis", metadata.AudioCodec)
+	assert.Equal(t, uint(2000000), metadata.VideoBitrate)
+	assert.Equal(t, uint(128000), metadata.AudioBitrate)
+
+	// a new media has new metadata
+	err = video.SetPipelineFromString(`
+    videotestsrc num-buffers=10 ! video/x-raw,width=160,height=120 !
+    videoconvert ! video/x-raw,format=RGBA !
+    appsink name={{ .AppSinkElementName }}`)
+	assert.Nil(t, err)
+	assert.Equal(t, streamer.Metadata{}, video.Metadata())
+}
@@ -0,0 +1,38 @@
+/*
+Package visualizer proposes widgets that display the audio of a playing media.
+
+The Spectrum widget draws the magnitudes of the frequency bands of the audio as bars. It can be
+attached to any source that sends the spectrum, like the video.Viewer (and so the video.Player) or
+the audio.Player:
+
+	player := video.NewPlayer()
+	spectrum := visualizer.NewSpectrum()
+	spectrum.SetBands(32)
+	spectrum.SetInterval(50 * time.Millisecond)
+	spectrum.Attach(player)
+
+	player.Open(uri)
+	player.Play()
+
+The LevelMeter widget is a vertical VU meter that draws the RMS and peak levels of each audio
+channel. It is attached the same way:
+
+	meter := visualizer.NewLevelMeter()
+	meter.Attach(player)
+
+The video.Player can also display a level meter beside its volume slider, with SetLevelMeterVisible(true).
+
+The default pipelines have "spectrum" and "level" elements. For custom pipelines, add them in the
+audio branch, named with SpectrumElementName and LevelElementName:
+
+	audiotestsrc name={{ .InputElementName }} !
+	audioconvert !
+	spectrum name={{ .SpectrumElementName }} post-messages=false !
+	volume name={{ .VolumeElementName }} !
+	level name={{ .LevelElementName }} post-messages=false !
+	autoaudiosink
+
+The players make these elements post their messages only while a callback (or a widget) listens
+to them.
+*/
+package visualizer
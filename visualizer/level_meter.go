@@ -0,0 +1,185 @@
+package visualizer
+
+import (
+	"image/color"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// peakMarkerHeight is the height of the line that marks the peak level.
+const peakMarkerHeight = 2
+
+var _ fyne.Widget = (*LevelMeter)(nil)
+
+// LevelSource is a media that sends the audio levels of its channels. It is implemented by
+// video.Viewer, video.Player and audio.Player.
+type LevelSource interface {
+	SetOnAudioLevel(func(rms, peak []float64))
+}
+
+// LevelMeter widget is a vertical VU meter. It draws one bar per audio channel for the RMS level,
+// and a line for the peak level.
+type LevelMeter struct {
+	widget.BaseWidget
+
+	// Threshold is the lowest level in dB, that is an empty bar. A level of 0 dB is a full bar.
+	Threshold float64
+
+	// BarColor is the color of the RMS bars. If nil, the primary color of the theme is used.
+	BarColor color.Color
+
+	// PeakColor is the color of the peak lines. If nil, the foreground color of the theme is used.
+	PeakColor color.Color
+
+	mu   sync.Mutex // protects the levels that are sent by the bus
+	rms  []float64
+	peak []float64
+}
+
+// NewLevelMeter creates a new level meter widget.
+func NewLevelMeter() *LevelMeter {
+	m := &LevelMeter{
+		Threshold: DefaultThreshold,
+	}
+	m.ExtendBaseWidget(m)
+	return m
+}
+
+// Attach sends the audio levels of the given source to the widget.
+func (m *LevelMeter) Attach(source LevelSource) {
+	source.SetOnAudioLevel(m.SetLevels)
+}
+
+// CreateRenderer creates a renderer for the level meter widget.
+//
+// Implements: fyne.Widget
+func (m *LevelMeter) CreateRenderer() fyne.WidgetRenderer {
+	r := &levelMeterRenderer{
+		parent:     m,
+		background: canvas.NewRectangle(theme.InputBackgroundColor()),
+	}
+	r.Refresh()
+	return r
+}
+
+// Levels returns a copy of the displayed RMS and peak levels, in dB.
+func (m *LevelMeter) Levels() (rms, peak []float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rms = append([]float64(nil), m.rms...)
+	peak = append([]float64(nil), m.peak...)
+	return rms, peak
+}
+
+// Reset empties the meter, e.g. when the media is stopped.
+func (m *LevelMeter) Reset() {
+	m.SetLevels(nil, nil)
+}
+
+// SetLevels displays the given RMS and peak levels of each channel, in dB. It is the function that
+// is given to the attached source.
+func (m *LevelMeter) SetLevels(rms, peak []float64) {
+	m.mu.Lock()
+	m.rms = append(m.rms[:0], rms...)
+	m.peak = append(m.peak[:0], peak...)
+	m.mu.Unlock()
+	m.Refresh()
+}
+
+var _ fyne.WidgetRenderer = (*levelMeterRenderer)(nil)
+
+// levelMeterRenderer draws a bar and a peak line per channel, over a background.
+type levelMeterRenderer struct {
+	parent     *LevelMeter
+	background *canvas.Rectangle
+	bars       []*canvas.Rectangle
+	peaks      []*canvas.Rectangle
+	rms        []float32
+	peak       []float32
+}
+
+// Destroy is an internal function from the fyne.WidgetRenderer interface.
+func (r *levelMeterRenderer) Destroy() { /* no op */ }
+
+// Layout implements the fyne.WidgetRenderer interface. The channels are side by side, the bars
+// are aligned on the bottom.
+func (r *levelMeterRenderer) Layout(size fyne.Size) {
+	r.background.Resize(size)
+	if len(r.bars) == 0 {
+		return
+	}
+	gap := float32(1)
+	step := (size.Width - gap) / float32(len(r.bars))
+	for i, bar := range r.bars {
+		x := gap + float32(i)*step
+		height := size.Height * r.rms[i]
+		bar.Resize(fyne.NewSize(step-gap, height))
+		bar.Move(fyne.NewPos(x, size.Height-height))
+
+		y := size.Height - size.Height*r.peak[i]
+		if y > size.Height-peakMarkerHeight {
+			y = size.Height - peakMarkerHeight
+		}
+		r.peaks[i].Resize(fyne.NewSize(step-gap, peakMarkerHeight))
+		r.peaks[i].Move(fyne.NewPos(x, y))
+	}
+}
+
+// MinSize implements the fyne.WidgetRenderer interface. The meter is as high as the vertical sliders.
+func (r *levelMeterRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(theme.Padding()*2, theme.IconInlineSize()*2)
+}
+
+// Objects implements the fyne.WidgetRenderer interface.
+func (r *levelMeterRenderer) Objects() []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{r.background}
+	for i := range r.bars {
+		objects = append(objects, r.bars[i], r.peaks[i])
+	}
+	return objects
+}
+
+// Refresh implements the fyne.WidgetRenderer interface. It updates the bars with the last levels.
+func (r *levelMeterRenderer) Refresh() {
+	rms, peak := r.parent.Levels()
+
+	barColor := r.parent.BarColor
+	if barColor == nil {
+		barColor = theme.PrimaryColor()
+	}
+	peakColor := r.parent.PeakColor
+	if peakColor == nil {
+		peakColor = theme.ForegroundColor()
+	}
+
+	for len(r.bars) < len(rms) {
+		r.bars = append(r.bars, canvas.NewRectangle(barColor))
+		r.peaks = append(r.peaks, canvas.NewRectangle(peakColor))
+	}
+	r.bars = r.bars[:len(rms)]
+	r.peaks = r.peaks[:len(rms)]
+
+	r.rms, r.peak = r.rms[:0], r.peak[:0]
+	for i := range rms {
+		r.rms = append(r.rms, dbRatio(rms[i], r.parent.Threshold))
+		p := rms[i]
+		if i < len(peak) {
+			p = peak[i]
+		}
+		r.peak = append(r.peak, dbRatio(p, r.parent.Threshold))
+	}
+
+	r.Layout(r.parent.Size())
+	r.background.FillColor = theme.InputBackgroundColor()
+	r.background.Refresh()
+	for i := range r.bars {
+		r.bars[i].FillColor = barColor
+		r.bars[i].Refresh()
+		r.peaks[i].FillColor = peakColor
+		r.peaks[i].Refresh()
+	}
+}
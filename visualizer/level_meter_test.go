@@ -0,0 +1,54 @@
+package visualizer
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"github.com/metal3d/fyne-streamer/audio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelMeterBars(t *testing.T) {
+	meter := NewLevelMeter()
+	meter.Resize(fyne.NewSize(20, 60))
+	renderer := test.WidgetRenderer(meter)
+	assert.Len(t, renderer.Objects(), 1) // the background
+
+	meter.SetLevels([]float64{-30, -60}, []float64{0, -30})
+	objects := renderer.Objects()
+	assert.Len(t, objects, 5)
+	assert.Equal(t, float32(30), objects[1].Size().Height) // left rms
+	assert.Equal(t, float32(0), objects[2].Position().Y)   // left peak
+	assert.Equal(t, float32(0), objects[3].Size().Height)  // right rms
+	assert.Equal(t, float32(30), objects[4].Position().Y)  // right peak
+
+	meter.Reset()
+	assert.Len(t, renderer.Objects(), 1)
+}
+
+func TestLevelMeterFromSource(t *testing.T) {
+	player := audio.NewPlayer()
+	meter := NewLevelMeter()
+	_ = test.WidgetRenderer(meter)
+
+	meter.Attach(player)
+	assert.Nil(t, player.SetPipelineFromString(testPipeline))
+	assert.Nil(t, player.Play())
+	defer player.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if rms, _ := meter.Levels(); len(rms) > 0 {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	rms, peak := meter.Levels()
+	assert.NotEmpty(t, rms)
+	assert.Len(t, peak, len(rms))
+	for i := range rms {
+		assert.True(t, peak[i] >= rms[i], "peak %v should be greater than rms %v", peak[i], rms[i])
+	}
+}
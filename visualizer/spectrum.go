@@ -0,0 +1,218 @@
+package visualizer
+
+import (
+	"fmt"
+	"image/color"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	streamer "github.com/metal3d/fyne-streamer"
+)
+
+const (
+	// DefaultBands is the default number of frequency bands of the Spectrum widget.
+	DefaultBands = 32
+
+	// DefaultInterval is the default interval between two updates of the Spectrum widget.
+	DefaultInterval = 50 * time.Millisecond
+
+	// DefaultThreshold is the default lowest magnitude, in dB, displayed by the widgets.
+	DefaultThreshold = -60.0
+)
+
+var _ fyne.Widget = (*Spectrum)(nil)
+
+// dbRatio returns the ratio of a value in dB, between the threshold (0) and 0 dB (1).
+func dbRatio(db, threshold float64) float32 {
+	if threshold >= 0 {
+		return 0
+	}
+	ratio := (db - threshold) / -threshold
+	if ratio < 0 {
+		return 0
+	}
+	if ratio > 1 {
+		return 1
+	}
+	return float32(ratio)
+}
+
+// SpectrumSource is a media that sends the magnitudes of the frequency bands of its audio. It is
+// implemented by video.Viewer, video.Player and audio.Player.
+type SpectrumSource interface {
+	SetOnSpectrum(func([]float64))
+	SetSpectrumBands(int) error
+	SetSpectrumInterval(time.Duration) error
+}
+
+// Spectrum widget draws the magnitudes of the frequency bands of an audio stream as bars, from the
+// lowest frequency on the left to the highest on the right.
+type Spectrum struct {
+	widget.BaseWidget
+
+	// Threshold is the lowest magnitude in dB, that is an empty bar. A magnitude of 0 dB is a full bar.
+	Threshold float64
+
+	// BarColor is the color of the bars. If nil, the primary color of the theme is used.
+	BarColor color.Color
+
+	mu         sync.Mutex // protects the magnitudes that are sent by the bus
+	magnitudes []float64
+	bands      int
+	interval   time.Duration
+	source     SpectrumSource
+}
+
+// NewSpectrum creates a new spectrum widget with DefaultBands bands and DefaultInterval interval.
+func NewSpectrum() *Spectrum {
+	s := &Spectrum{
+		Threshold: DefaultThreshold,
+		bands:     DefaultBands,
+		interval:  DefaultInterval,
+	}
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+// Attach sends the spectrum of the given source to the widget. The number of bands and the
+// interval of the widget are applied to the source.
+func (s *Spectrum) Attach(source SpectrumSource) error {
+	s.source = source
+	source.SetOnSpectrum(s.SetMagnitudes)
+	if err := source.SetSpectrumBands(s.bands); err != nil {
+		return err
+	}
+	return source.SetSpectrumInterval(s.interval)
+}
+
+// Bands returns the number of frequency bands.
+func (s *Spectrum) Bands() int {
+	return s.bands
+}
+
+// CreateRenderer creates a renderer for the spectrum widget.
+//
+// Implements: fyne.Widget
+func (s *Spectrum) CreateRenderer() fyne.WidgetRenderer {
+	return &spectrumRenderer{parent: s}
+}
+
+// Interval returns the interval between two updates.
+func (s *Spectrum) Interval() time.Duration {
+	return s.interval
+}
+
+// Magnitudes returns a copy of the displayed magnitudes, in dB.
+func (s *Spectrum) Magnitudes() []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	magnitudes := make([]float64, len(s.magnitudes))
+	copy(magnitudes, s.magnitudes)
+	return magnitudes
+}
+
+// SetBands sets the number of frequency bands. If a source is attached, it is changed too.
+func (s *Spectrum) SetBands(bands int) error {
+	if bands <= 0 {
+		return fmt.Errorf("%w: %d bands", streamer.ErrInvalidSpectrum, bands)
+	}
+	s.bands = bands
+	if s.source == nil {
+		return nil
+	}
+	return s.source.SetSpectrumBands(bands)
+}
+
+// SetInterval sets the interval between two updates. If a source is attached, it is changed too.
+func (s *Spectrum) SetInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: interval %v", streamer.ErrInvalidSpectrum, interval)
+	}
+	s.interval = interval
+	if s.source == nil {
+		return nil
+	}
+	return s.source.SetSpectrumInterval(interval)
+}
+
+// SetMagnitudes displays the given magnitudes, in dB. It is the function that is given to the
+// attached source, but it can be used to draw any other values.
+func (s *Spectrum) SetMagnitudes(magnitudes []float64) {
+	s.mu.Lock()
+	s.magnitudes = append(s.magnitudes[:0], magnitudes...)
+	s.mu.Unlock()
+	s.Refresh()
+}
+
+var _ fyne.WidgetRenderer = (*spectrumRenderer)(nil)
+
+// spectrumRenderer draws one rectangle per band.
+type spectrumRenderer struct {
+	parent *Spectrum
+	bars   []*canvas.Rectangle
+	ratios []float32
+}
+
+// Destroy is an internal function from the fyne.WidgetRenderer interface.
+func (r *spectrumRenderer) Destroy() { /* no op */ }
+
+// Layout implements the fyne.WidgetRenderer interface. The bars are aligned on the bottom.
+func (r *spectrumRenderer) Layout(size fyne.Size) {
+	if len(r.bars) == 0 {
+		return
+	}
+	step := size.Width / float32(len(r.bars))
+	gap := float32(0)
+	if step > 2*theme.Padding() {
+		gap = theme.Padding() / 2
+	}
+	for i, bar := range r.bars {
+		height := size.Height * r.ratios[i]
+		bar.Resize(fyne.NewSize(step-gap, height))
+		bar.Move(fyne.NewPos(float32(i)*step, size.Height-height))
+	}
+}
+
+// MinSize implements the fyne.WidgetRenderer interface.
+func (r *spectrumRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(theme.IconInlineSize()*4, theme.IconInlineSize()*2)
+}
+
+// Objects implements the fyne.WidgetRenderer interface.
+func (r *spectrumRenderer) Objects() []fyne.CanvasObject {
+	objects := make([]fyne.CanvasObject, len(r.bars))
+	for i, bar := range r.bars {
+		objects[i] = bar
+	}
+	return objects
+}
+
+// Refresh implements the fyne.WidgetRenderer interface. It updates the bars with the last magnitudes.
+func (r *spectrumRenderer) Refresh() {
+	magnitudes := r.parent.Magnitudes()
+
+	barColor := r.parent.BarColor
+	if barColor == nil {
+		barColor = theme.PrimaryColor()
+	}
+
+	for len(r.bars) < len(magnitudes) {
+		r.bars = append(r.bars, canvas.NewRectangle(barColor))
+	}
+	r.bars = r.bars[:len(magnitudes)]
+
+	r.ratios = r.ratios[:0]
+	for _, magnitude := range magnitudes {
+		r.ratios = append(r.ratios, dbRatio(magnitude, r.parent.Threshold))
+	}
+
+	r.Layout(r.parent.Size())
+	for _, bar := range r.bars {
+		bar.FillColor = barColor
+		bar.Refresh()
+	}
+}
@@ -0,0 +1,61 @@
+package visualizer
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"github.com/metal3d/fyne-streamer/audio"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPipeline = `
+    audiotestsrc name={{ .InputElementName }} wave=white-noise num-buffers=200 !
+    audioconvert !
+    spectrum name={{ .SpectrumElementName }} post-messages=false !
+    volume name={{ .VolumeElementName }} !
+    level name={{ .LevelElementName }} post-messages=false !
+    fakesink sync=true
+    `
+
+func TestSpectrumBars(t *testing.T) {
+	spectrum := NewSpectrum()
+	spectrum.Resize(fyne.NewSize(100, 60))
+	renderer := test.WidgetRenderer(spectrum)
+	assert.Empty(t, renderer.Objects())
+
+	spectrum.SetMagnitudes([]float64{0, -30, -60, -90})
+	objects := renderer.Objects()
+	assert.Len(t, objects, 4)
+	assert.Equal(t, float32(60), objects[0].Size().Height)
+	assert.Equal(t, float32(30), objects[1].Size().Height)
+	assert.Equal(t, float32(0), objects[2].Size().Height)
+	assert.Equal(t, float32(0), objects[3].Size().Height)
+
+	assert.NotNil(t, spectrum.SetBands(0))
+	assert.NotNil(t, spectrum.SetInterval(0))
+}
+
+func TestSpectrumFromSource(t *testing.T) {
+	player := audio.NewPlayer()
+	spectrum := NewSpectrum()
+	_ = test.WidgetRenderer(spectrum)
+
+	assert.Nil(t, spectrum.SetBands(16))
+	assert.Nil(t, spectrum.Attach(player))
+	assert.Nil(t, player.SetPipelineFromString(testPipeline))
+	assert.Nil(t, player.Play())
+	defer player.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(spectrum.Magnitudes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.Len(t, spectrum.Magnitudes(), 16)
+
+	// the bands can be changed while playing
+	assert.Nil(t, spectrum.SetBands(8))
+	time.Sleep(500 * time.Millisecond)
+	assert.Len(t, spectrum.Magnitudes(), 8)
+}